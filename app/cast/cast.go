@@ -0,0 +1,28 @@
+package cast
+
+import (
+	"fmt"
+
+	"gorm.io/gorm/schema"
+)
+
+// Register the serializers used in the struct tags, e.g.
+// `gorm:"serializer:encrypted"`. The json serializer is built in gorm.
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+	schema.RegisterSerializer("hashed", HashedSerializer{})
+	schema.RegisterSerializer("enum", EnumSerializer{})
+}
+
+// Convert the database value to string
+func toString(dbValue interface{}) (string, error) {
+	switch value := dbValue.(type) {
+	case nil:
+		return "", nil
+	case []byte:
+		return string(value), nil
+	case string:
+		return value, nil
+	}
+	return "", fmt.Errorf("unsupported database value %#v", dbValue)
+}
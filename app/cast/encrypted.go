@@ -0,0 +1,124 @@
+package cast
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"govel/app/encryption"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// Value encrypted with APP_KEY in the database, e.g. Secret cast.Encrypted[string].
+// The value is stored as json if it is not a string, the encrypted value is
+// prefixed with "encrypted:".
+type Encrypted[T any] struct {
+	Val T
+}
+
+func (encrypted *Encrypted[T]) Scan(dbValue interface{}) error {
+	raw, err := toString(dbValue)
+	if err != nil || raw == "" {
+		return err
+	}
+	if raw, err = decrypt(raw); err != nil {
+		return err
+	}
+	return decode(raw, &encrypted.Val)
+}
+
+func (encrypted Encrypted[T]) Value() (driver.Value, error) {
+	raw, err := encode(encrypted.Val)
+	if err != nil || raw == "" {
+		return raw, err
+	}
+	return encrypt(raw), nil
+}
+
+func (Encrypted[T]) GormDataType() string {
+	return "text"
+}
+
+func (encrypted Encrypted[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(encrypted.Val)
+}
+
+func (encrypted *Encrypted[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &encrypted.Val)
+}
+
+// Encrypt the field with APP_KEY, e.g. `gorm:"serializer:encrypted"`. The
+// empty value is stored as is.
+type EncryptedSerializer struct{}
+
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	raw, err := toString(dbValue)
+	if err != nil {
+		return err
+	}
+
+	fieldValue := reflect.New(field.FieldType)
+	if raw != "" {
+		if raw, err = decrypt(raw); err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+		if err := decode(raw, fieldValue.Interface()); err != nil {
+			return err
+		}
+	}
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	raw, err := encode(fieldValue)
+	if err != nil || raw == "" {
+		return raw, err
+	}
+	return encrypt(raw), nil
+}
+
+// Find the encrypted value by exact match, store the index of the value in
+// other column, e.g. email_index
+func BlindIndex(value string) string {
+	if value == "" {
+		return ""
+	}
+	return encryption.Default().BlindIndex(value)
+}
+
+// Encrypt the value and mark it with the prefix
+func encrypt(raw string) string {
+	return encryption.Prefix + encryption.Default().EncryptString(raw)
+}
+
+// Decrypt the value marked with the prefix. The value without the prefix is
+// returned as is, so the rows stored before the column was encrypted are
+// still readable. The marked value failing to decrypt is an error, e.g. the
+// APP_KEY was changed.
+func decrypt(raw string) (string, error) {
+	if !strings.HasPrefix(raw, encryption.Prefix) {
+		return raw, nil
+	}
+	return encryption.Default().DecryptString(strings.TrimPrefix(raw, encryption.Prefix))
+}
+
+// Encode the string as is and the other types as json
+func encode(value interface{}) (string, error) {
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
+
+func decode(raw string, dst interface{}) error {
+	if text, ok := dst.(*string); ok {
+		*text = raw
+		return nil
+	}
+	return json.Unmarshal([]byte(raw), dst)
+}
@@ -0,0 +1,44 @@
+package cast
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// Restrict the string field to the values of the enum tag, e.g.
+// Status string `gorm:"serializer:enum" enum:"draft,published"`
+type EnumSerializer struct{}
+
+func (EnumSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	raw, err := toString(dbValue)
+	if err != nil {
+		return err
+	}
+	if err := checkEnum(field, raw); err != nil {
+		return err
+	}
+	field.ReflectValueOf(ctx, dst).SetString(raw)
+	return nil
+}
+
+func (EnumSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value := reflect.ValueOf(fieldValue).String()
+	return value, checkEnum(field, value)
+}
+
+func checkEnum(field *schema.Field, value string) error {
+	options := strings.Split(field.Tag.Get("enum"), ",")
+	for _, option := range options {
+		if value == strings.TrimSpace(option) {
+			return nil
+		}
+	}
+	if value == "" && !field.NotNull {
+		return nil
+	}
+	return fmt.Errorf("%s must be one of %s, got %q", field.Name, strings.Join(options, ", "), value)
+}
@@ -0,0 +1,56 @@
+package cast
+
+import (
+	"context"
+	"database/sql/driver"
+	"govel/app/hashing"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// Value hashed with the default hasher before saved, e.g. Pin cast.Hashed.
+// The value is never hashed twice.
+type Hashed string
+
+func (hashed *Hashed) Scan(dbValue interface{}) error {
+	raw, err := toString(dbValue)
+	*hashed = Hashed(raw)
+	return err
+}
+
+func (hashed Hashed) Value() (driver.Value, error) {
+	return hash(string(hashed)), nil
+}
+
+func (Hashed) GormDataType() string {
+	return "varchar(255)"
+}
+
+// Check the plain value match the hash
+func (hashed Hashed) Check(value string) bool {
+	return hashing.Default().Check(value, string(hashed))
+}
+
+// Hash the string field, e.g. `gorm:"serializer:hashed"`
+type HashedSerializer struct{}
+
+func (HashedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	raw, err := toString(dbValue)
+	if err != nil {
+		return err
+	}
+	field.ReflectValueOf(ctx, dst).SetString(raw)
+	return nil
+}
+
+func (HashedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	return hash(reflect.ValueOf(fieldValue).String()), nil
+}
+
+func hash(value string) string {
+	if value == "" || hashing.Algorithm(value) != "" {
+		return value
+	}
+	return hashing.Default().Make(value)
+}
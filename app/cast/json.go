@@ -0,0 +1,37 @@
+package cast
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Value stored as json, e.g. Settings cast.JSON[map[string]string]. Use
+// `gorm:"serializer:json"` to keep the field type instead.
+type JSON[T any] struct {
+	Val T
+}
+
+func (value *JSON[T]) Scan(dbValue interface{}) error {
+	raw, err := toString(dbValue)
+	if err != nil || raw == "" {
+		return err
+	}
+	return json.Unmarshal([]byte(raw), &value.Val)
+}
+
+func (value JSON[T]) Value() (driver.Value, error) {
+	encoded, err := json.Marshal(value.Val)
+	return string(encoded), err
+}
+
+func (JSON[T]) GormDataType() string {
+	return "text"
+}
+
+func (value JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(value.Val)
+}
+
+func (value *JSON[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &value.Val)
+}
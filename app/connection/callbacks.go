@@ -0,0 +1,33 @@
+package connection
+
+import "gorm.io/gorm"
+
+// Register the callbacks around every operation of gorm, the functions get
+// the operation like "query" or "create"
+func registerAround(db *gorm.DB, name string, before func(operation string) func(*gorm.DB), after func(operation string) func(*gorm.DB)) error {
+	callbacks := db.Callback()
+	for _, processor := range []struct {
+		operation string
+		before    interface {
+			Register(name string, fn func(*gorm.DB)) error
+		}
+		after interface {
+			Register(name string, fn func(*gorm.DB)) error
+		}
+	}{
+		{"query", callbacks.Query().Before("gorm:query"), callbacks.Query().After("gorm:query")},
+		{"row", callbacks.Row().Before("gorm:row"), callbacks.Row().After("gorm:row")},
+		{"create", callbacks.Create().Before("gorm:create"), callbacks.Create().After("gorm:create")},
+		{"update", callbacks.Update().Before("gorm:update"), callbacks.Update().After("gorm:update")},
+		{"delete", callbacks.Delete().Before("gorm:delete"), callbacks.Delete().After("gorm:delete")},
+		{"raw", callbacks.Raw().Before("gorm:raw"), callbacks.Raw().After("gorm:raw")},
+	} {
+		if err := processor.before.Register(name+"_started", before(processor.operation)); err != nil {
+			return err
+		}
+		if err := processor.after.Register(name, after(processor.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
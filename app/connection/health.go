@@ -0,0 +1,171 @@
+package connection
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Result of the periodic ping of the connection pool
+type Health struct {
+	Connection string        `json:"connection"`
+	Healthy    bool          `json:"healthy"`
+	Error      string        `json:"error,omitempty"`
+	Latency    time.Duration `json:"latency"`
+	CheckedAt  time.Time     `json:"checked_at"`
+	Pings      int64         `json:"pings"`
+	Failures   int64         `json:"failures"`
+	// Failures in a row, reset on success
+	ConsecutiveFailures int64       `json:"consecutive_failures"`
+	Stats               sql.DBStats `json:"stats"`
+}
+
+type healthMonitor struct {
+	interval time.Duration
+	timeout  time.Duration
+	results  map[string]*Health
+	mutex    sync.RWMutex
+	stop     chan struct{}
+}
+
+// Ping the opened connections every interval, check them at once before the
+// first interval
+func (manager *Manager) StartHealthChecks(interval time.Duration, timeout time.Duration) {
+	manager.mutex.Lock()
+	if manager.monitor != nil {
+		manager.mutex.Unlock()
+		return
+	}
+	monitor := &healthMonitor{
+		interval: interval,
+		timeout:  timeout,
+		results:  map[string]*Health{},
+		stop:     make(chan struct{}),
+	}
+	manager.monitor = monitor
+	manager.mutex.Unlock()
+
+	manager.CheckHealth()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				manager.CheckHealth()
+			case <-monitor.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop the periodic pings
+func (manager *Manager) StopHealthChecks() {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.monitor != nil {
+		close(manager.monitor.stop)
+		manager.monitor = nil
+	}
+}
+
+// Ping the opened connections now
+func (manager *Manager) CheckHealth() {
+	manager.mutex.Lock()
+	monitor := manager.monitor
+	pools := map[string]*sql.DB{}
+	for name, connection := range manager.connections {
+		if sqlDB, err := connection.DB(); err == nil {
+			pools[name] = sqlDB
+		}
+	}
+	manager.mutex.Unlock()
+	if monitor == nil {
+		return
+	}
+
+	for name, pool := range pools {
+		ctx, cancel := context.WithTimeout(context.Background(), monitor.timeout)
+		start := time.Now()
+		err := pool.PingContext(ctx)
+		latency := time.Since(start)
+		cancel()
+
+		monitor.mutex.Lock()
+		health, ok := monitor.results[name]
+		if !ok {
+			health = &Health{Connection: name}
+			monitor.results[name] = health
+		}
+		health.Pings++
+		health.Healthy = err == nil
+		health.Error = ""
+		health.Latency = latency
+		health.CheckedAt = time.Now()
+		health.Stats = pool.Stats()
+		if err != nil {
+			health.Error = err.Error()
+			health.Failures++
+			health.ConsecutiveFailures++
+		} else {
+			health.ConsecutiveFailures = 0
+		}
+		monitor.mutex.Unlock()
+	}
+}
+
+// Latest results of the opened connections
+func (manager *Manager) Health() []Health {
+	manager.mutex.Lock()
+	monitor := manager.monitor
+	manager.mutex.Unlock()
+	if monitor == nil {
+		return nil
+	}
+
+	monitor.mutex.RLock()
+	defer monitor.mutex.RUnlock()
+	results := []Health{}
+	for _, name := range manager.Names() {
+		if health, ok := monitor.results[name]; ok {
+			results = append(results, *health)
+		}
+	}
+	return results
+}
+
+// Check every opened connection passed the latest ping
+func (manager *Manager) Ready() bool {
+	results := manager.Health()
+	if results == nil {
+		return false
+	}
+	for _, health := range results {
+		if !health.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// Ping the opened connections now, fail on the first error
+func (manager *Manager) Ping(ctx context.Context) error {
+	manager.mutex.Lock()
+	pools := map[string]*sql.DB{}
+	for name, connection := range manager.connections {
+		if sqlDB, err := connection.DB(); err == nil {
+			pools[name] = sqlDB
+		}
+	}
+	manager.mutex.Unlock()
+
+	for name, pool := range pools {
+		if err := pool.PingContext(ctx); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
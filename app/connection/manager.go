@@ -0,0 +1,90 @@
+package connection
+
+import (
+	"database/sql"
+	"sort"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// Open the named connection, panic if the connection is not configured
+type Opener func(name string) *gorm.DB
+
+// Named database connections opened on first use
+type Manager struct {
+	defaultName string
+	open        Opener
+	connections map[string]*gorm.DB
+	monitor     *healthMonitor
+	mutex       sync.Mutex
+}
+
+func NewManager(defaultName string, open Opener) *Manager {
+	return &Manager{
+		defaultName: defaultName,
+		open:        open,
+		connections: map[string]*gorm.DB{},
+	}
+}
+
+// Get the connection by name like "analytics", open it if needed
+func (manager *Manager) Connection(name string) *gorm.DB {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if connection, ok := manager.connections[name]; ok {
+		return connection
+	}
+	connection := manager.open(name)
+	manager.connections[name] = connection
+	return connection
+}
+
+// Get the connection of DB_CONNECTION
+func (manager *Manager) Default() *gorm.DB {
+	return manager.Connection(manager.defaultName)
+}
+
+func (manager *Manager) DefaultName() string {
+	return manager.defaultName
+}
+
+// Names of the opened connections
+func (manager *Manager) Names() []string {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	names := []string{}
+	for name := range manager.connections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close the opened connections and their replicas
+func (manager *Manager) Close() error {
+	manager.StopHealthChecks()
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	var closeErr error
+	for name, connection := range manager.connections {
+		pools := []*sql.DB{}
+		if sqlDB, err := connection.DB(); err == nil {
+			pools = append(pools, sqlDB)
+		}
+		if plugin, ok := connection.Config.Plugins[resolverName]; ok {
+			pools = append(pools, plugin.(*Resolver).replicas...)
+		}
+		for _, pool := range pools {
+			if err := pool.Close(); err != nil && closeErr == nil {
+				closeErr = err
+			}
+		}
+		delete(manager.connections, name)
+	}
+	return closeErr
+}
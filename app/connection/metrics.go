@@ -0,0 +1,81 @@
+package connection
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+const (
+	metricsName       = "govel:metrics"
+	metricsStartedKey = "govel:metrics_started"
+)
+
+// Gorm plugin recording the durations of the queries by operation and table,
+// and the pool stats of the connection
+type Metrics struct {
+	// Name of the connection, the connection label
+	Connection string
+	Registerer prometheus.Registerer
+}
+
+func (plugin *Metrics) Name() string {
+	return metricsName
+}
+
+func (plugin *Metrics) Initialize(db *gorm.DB) error {
+	durations := register(plugin.Registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of the database queries in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"connection", "operation", "table"}))
+	failures := register(plugin.Registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Number of the failed database queries.",
+	}, []string{"connection", "operation", "table"}))
+
+	err := registerAround(db, metricsName, func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			db.InstanceSet(metricsStartedKey, time.Now())
+		}
+	}, func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			started, ok := db.InstanceGet(metricsStartedKey)
+			if !ok {
+				return
+			}
+			// Cloned, the table may be a string of the request
+			table := strings.Clone(db.Statement.Table)
+			durations.WithLabelValues(plugin.Connection, operation, table).Observe(time.Since(started.(time.Time)).Seconds())
+			if db.Error != nil && db.Error != gorm.ErrRecordNotFound {
+				failures.WithLabelValues(plugin.Connection, operation, table).Inc()
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// The go_sql_* pool stats labeled with the connection as db_name
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return plugin.Registerer.Register(collectors.NewDBStatsCollector(sqlDB, plugin.Connection))
+}
+
+// Register the collector or get the one registered by another connection
+func register[T prometheus.Collector](registerer prometheus.Registerer, collector T) T {
+	if err := registerer.Register(collector); err != nil {
+		registered := prometheus.AlreadyRegisteredError{}
+		if errors.As(err, &registered) {
+			return registered.ExistingCollector.(T)
+		}
+		panic(err)
+	}
+	return collector
+}
@@ -0,0 +1,156 @@
+package connection
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils"
+)
+
+const (
+	queryLogName    = "govel:query_log"
+	queryStartedKey = "govel:query_started"
+)
+
+// Query run in the context of the query log
+type Query struct {
+	// SQL with the values, for debugging only
+	SQL string `json:"sql"`
+	// SQL with the placeholders, same for the queries differing by values
+	Statement string        `json:"-"`
+	Duration  time.Duration `json:"duration"`
+	Rows      int64         `json:"rows"`
+	Error     string        `json:"error,omitempty"`
+	// File and line of the app code running the query
+	Caller string `json:"caller,omitempty"`
+}
+
+// Statement run many times by one request, likely an N+1 query
+type RepeatedQuery struct {
+	Statement string
+	Count     int
+	// Caller of the first run
+	Caller string
+}
+
+// Queries of a request, safe for concurrent use
+type QueryLog struct {
+	queries []Query
+	mutex   sync.Mutex
+}
+
+type queryLogKey struct{}
+
+// Start logging the queries run with the context, e.g. db.WithContext(ctx)
+func WithQueryLog(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(queryLogKey{}).(*QueryLog); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, queryLogKey{}, &QueryLog{})
+}
+
+// Get the query log of the context, nil if not logging
+func QueryLogFrom(ctx context.Context) *QueryLog {
+	if ctx == nil {
+		return nil
+	}
+	log, _ := ctx.Value(queryLogKey{}).(*QueryLog)
+	return log
+}
+
+func (log *QueryLog) Queries() []Query {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+	return append([]Query{}, log.queries...)
+}
+
+// Last failed query, nil if none failed
+func (log *QueryLog) Failed() *Query {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+	for i := len(log.queries) - 1; i >= 0; i-- {
+		if log.queries[i].Error != "" {
+			query := log.queries[i]
+			return &query
+		}
+	}
+	return nil
+}
+
+// SQL of the last failed query, empty if none failed
+func (log *QueryLog) FailedSQL() string {
+	if failed := log.Failed(); failed != nil {
+		return failed.SQL
+	}
+	return ""
+}
+
+// Statements run at least min times in the order of their first run
+func (log *QueryLog) Repeated(min int) []RepeatedQuery {
+	counts := map[string]int{}
+	repeated := []RepeatedQuery{}
+	for _, query := range log.Queries() {
+		counts[query.Statement]++
+		if counts[query.Statement] == 1 {
+			repeated = append(repeated, RepeatedQuery{Statement: query.Statement, Caller: query.Caller})
+		}
+	}
+
+	result := []RepeatedQuery{}
+	for _, query := range repeated {
+		if query.Count = counts[query.Statement]; query.Count >= min {
+			result = append(result, query)
+		}
+	}
+	return result
+}
+
+func (log *QueryLog) add(query Query) {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+	log.queries = append(log.queries, query)
+}
+
+// Gorm plugin adding the queries to the query log of the statement context
+type QueryLogger struct{}
+
+func (logger *QueryLogger) Name() string {
+	return queryLogName
+}
+
+func (logger *QueryLogger) Initialize(db *gorm.DB) error {
+	return registerAround(db, queryLogName, func(operation string) func(*gorm.DB) {
+		return startQuery
+	}, func(operation string) func(*gorm.DB) {
+		return logQuery
+	})
+}
+
+func startQuery(db *gorm.DB) {
+	if QueryLogFrom(db.Statement.Context) != nil {
+		db.InstanceSet(queryStartedKey, time.Now())
+	}
+}
+
+func logQuery(db *gorm.DB) {
+	log := QueryLogFrom(db.Statement.Context)
+	if log == nil {
+		return
+	}
+
+	query := Query{
+		SQL:       db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...),
+		Statement: db.Statement.SQL.String(),
+		Rows:      db.RowsAffected,
+		Caller:    utils.FileWithLineNum(),
+	}
+	if started, ok := db.InstanceGet(queryStartedKey); ok {
+		query.Duration = time.Since(started.(time.Time))
+	}
+	if db.Error != nil {
+		query.Error = db.Error.Error()
+	}
+	log.add(query)
+}
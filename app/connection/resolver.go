@@ -0,0 +1,82 @@
+package connection
+
+import (
+	"database/sql"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+const resolverName = "govel:resolver"
+
+// Send the reads to the replicas and the writes to the primary. The reads
+// in a transaction, with locking or forced by UsePrimary stay on the primary.
+type Resolver struct {
+	replicas []*sql.DB
+	sticky   bool
+	next     uint64
+}
+
+// Make the resolver of the replicas, read from the primary after a write in
+// the same sticky context if sticky
+func NewResolver(replicas []*sql.DB, sticky bool) *Resolver {
+	return &Resolver{
+		replicas: replicas,
+		sticky:   sticky,
+	}
+}
+
+func (resolver *Resolver) Name() string {
+	return resolverName
+}
+
+func (resolver *Resolver) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register("govel:resolve_replica", resolver.resolve); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("govel:resolve_replica", resolver.resolve); err != nil {
+		return err
+	}
+
+	// Remember the writes for the sticky reads
+	for _, processor := range []interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}{
+		db.Callback().Create().After("gorm:create"),
+		db.Callback().Update().After("gorm:update"),
+		db.Callback().Delete().After("gorm:delete"),
+		db.Callback().Raw().After("gorm:raw"),
+	} {
+		if err := processor.Register("govel:mark_written", markWritten); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (resolver *Resolver) resolve(db *gorm.DB) {
+	if db.Error != nil || len(resolver.replicas) == 0 {
+		return
+	}
+	if _, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter); inTransaction {
+		return
+	}
+	if _, locking := db.Statement.Clauses["FOR"]; locking {
+		return
+	}
+	if primary, ok := db.Get(primaryKey); ok && primary.(bool) {
+		return
+	}
+	if resolver.sticky && written(db.Statement.Context) {
+		return
+	}
+
+	index := atomic.AddUint64(&resolver.next, 1) % uint64(len(resolver.replicas))
+	db.Statement.ConnPool = resolver.replicas[index]
+}
+
+func markWritten(db *gorm.DB) {
+	if db.Error == nil {
+		markContext(db.Statement.Context)
+	}
+}
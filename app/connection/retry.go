@@ -0,0 +1,31 @@
+package connection
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Call the function until it succeeds or the timeout passed, wait between
+// the attempts with exponential backoff from 200ms to 5s. The last error is
+// returned on timeout, zero timeout tries once.
+func Retry(timeout time.Duration, fn func(attempt int) error) error {
+	deadline := time.Now().Add(timeout)
+	wait := 200 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err := fn(attempt)
+		if err == nil {
+			return nil
+		}
+
+		// Add jitter so the instances don't retry at once
+		sleep := wait + time.Duration(rand.Int63n(int64(wait/5)+1))
+		if time.Now().Add(sleep).After(deadline) {
+			return err
+		}
+		time.Sleep(sleep)
+
+		if wait *= 2; wait > 5*time.Second {
+			wait = 5 * time.Second
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package connection
+
+import (
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils"
+)
+
+const (
+	slowQueryName       = "govel:slow_query"
+	slowQueryStartedKey = "govel:slow_query_started"
+)
+
+// Gorm plugin logging the queries slower than the threshold with the values
+// and the caller
+type SlowQueryLogger struct {
+	Connection string
+	Threshold  time.Duration
+}
+
+func (logger *SlowQueryLogger) Name() string {
+	return slowQueryName
+}
+
+func (logger *SlowQueryLogger) Initialize(db *gorm.DB) error {
+	return registerAround(db, slowQueryName, func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			db.InstanceSet(slowQueryStartedKey, time.Now())
+		}
+	}, func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			started, ok := db.InstanceGet(slowQueryStartedKey)
+			if !ok {
+				return
+			}
+			duration := time.Since(started.(time.Time))
+			if duration < logger.Threshold {
+				return
+			}
+
+			attrs := []slog.Attr{
+				slog.String("connection", logger.Connection),
+				slog.String("sql", db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...)),
+				slog.Float64("duration_ms", float64(duration.Microseconds())/1000),
+				slog.Int64("rows", db.RowsAffected),
+				slog.String("caller", utils.FileWithLineNum()),
+			}
+			if db.Error != nil {
+				attrs = append(attrs, slog.String("error", db.Error.Error()))
+			}
+			slog.LogAttrs(db.Statement.Context, slog.LevelWarn, "slow query", attrs...)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package connection
+
+import (
+	"context"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+const primaryKey = "govel:use_primary"
+
+type stickyKey struct{}
+
+type stickyState struct {
+	written int32
+}
+
+// Start the sticky scope of the request, the reads of the context go to the
+// primary after a write of the same context, e.g. db.WithContext(ctx)
+func WithSticky(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(stickyKey{}).(*stickyState); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, stickyKey{}, &stickyState{})
+}
+
+// Read from the primary, e.g. connection.UsePrimary(db).First(&user)
+func UsePrimary(db *gorm.DB) *gorm.DB {
+	return db.Set(primaryKey, true)
+}
+
+func markContext(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	if state, ok := ctx.Value(stickyKey{}).(*stickyState); ok {
+		atomic.StoreInt32(&state.written, 1)
+	}
+}
+
+func written(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	state, ok := ctx.Value(stickyKey{}).(*stickyState)
+	return ok && atomic.LoadInt32(&state.written) == 1
+}
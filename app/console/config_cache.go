@@ -0,0 +1,88 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"govel/app/helper"
+	"govel/config"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+func ConfigCacheCommand(configuration config.Config) Command {
+	return Command{
+		Name:        "config:cache",
+		Description: "Write the resolved config to " + config.CachePath + " for fast boot",
+		Handle: func(args []string) error {
+			// Resolve from the env files again, never from an old snapshot
+			if configuration.Cached() {
+				if err := removeConfigCache(); err != nil {
+					return err
+				}
+				configuration = config.New()
+				configuration.LoadEnv()
+			}
+
+			// Don't cache the invalid settings
+			if _, err := config.Load(configuration); err != nil {
+				return err
+			}
+
+			filename := helper.BasePath(config.CachePath)
+			if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+				return err
+			}
+			if err := config.WriteCache(configuration.All(), filename); err != nil {
+				return err
+			}
+			fmt.Println("Configuration cached to " + config.CachePath + ".")
+			return nil
+		},
+	}
+}
+
+func ConfigClearCommand() Command {
+	return Command{
+		Name:        "config:clear",
+		Description: "Remove the config cache",
+		Handle: func(args []string) error {
+			if err := removeConfigCache(); err != nil {
+				return err
+			}
+			fmt.Println("Configuration cache cleared.")
+			return nil
+		},
+	}
+}
+
+func ConfigShowCommand(configuration config.Config) Command {
+	return Command{
+		Name:        "config:show",
+		Description: "Print the effective config with secrets masked, e.g. config:show database",
+		Handle: func(args []string) error {
+			prefix := ""
+			if len(args) > 0 {
+				prefix = args[0]
+			}
+
+			output := config.Dump(configuration.All(), prefix)
+			if output == "" {
+				return errors.New("Config \"" + prefix + "\" is not defined.")
+			}
+			if configuration.Cached() {
+				fmt.Println("# Loaded from " + config.CachePath)
+			}
+			fmt.Println(output)
+			return nil
+		},
+	}
+}
+
+func removeConfigCache() error {
+	err := os.Remove(helper.BasePath(config.CachePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
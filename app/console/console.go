@@ -0,0 +1,67 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+type Command struct {
+	Name        string
+	Description string
+	Handle      func(args []string) error
+}
+
+// Run the command matched the first argument and exit with status 1 if it
+// failed, print the available commands if not found
+func Run(commands []Command, args []string) {
+	for _, command := range commands {
+		if len(args) > 0 && command.Name == args[0] {
+			if err := command.Handle(args[1:]); err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Command %q is not defined.\n\n", args[0])
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+	fmt.Println("Available commands:")
+	for _, command := range commands {
+		fmt.Printf("  %-20s %s\n", command.Name, command.Description)
+	}
+	if len(args) > 0 {
+		os.Exit(1)
+	}
+}
+
+// Get the option like --file=.env.production or --force, the flag without
+// value returns empty value and true
+func Option(args []string, name string) (string, bool) {
+	for _, arg := range args {
+		if arg == "--"+name {
+			return "", true
+		}
+		if strings.HasPrefix(arg, "--"+name+"=") {
+			return strings.TrimPrefix(arg, "--"+name+"="), true
+		}
+	}
+	return "", false
+}
+
+// Get the arguments without the options
+func Arguments(args []string) []string {
+	result := []string{}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			result = append(result, arg)
+		}
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+package console
+
+import (
+	"fmt"
+	"govel/config"
+	"strconv"
+	"strings"
+)
+
+func DBShowCommand(configuration config.Config) Command {
+	return Command{
+		Name:        "db:show",
+		Description: "Print the effective settings of the connection with secrets redacted, e.g. db:show analytics",
+		Handle: func(args []string) error {
+			name := configuration.Get("database.default")
+			if arguments := Arguments(args); len(arguments) > 0 {
+				name = arguments[0]
+			}
+			database, err := config.LoadConnectionConfig(configuration, name)
+			if err != nil {
+				return err
+			}
+
+			password := ""
+			if database.Password != "" {
+				password = "********"
+			}
+			rows := [][2]string{
+				{"Connection", database.Connection},
+				{"Driver", database.Driver},
+				{"Host", database.Host},
+				{"Port", strconv.Itoa(database.Port)},
+				{"Database", database.Database},
+				{"Username", database.Username},
+				{"Password", password},
+				{"Timezone", database.Timezone},
+				{"Charset", database.Charset},
+				{"SSL mode", database.SSLMode},
+				{"SSL root cert", database.SSLRootCert},
+				{"SSL cert", database.SSLCert},
+				{"SSL key", database.SSLKey},
+				{"Options", database.Options},
+				{"Read hosts", strings.Join(database.ReadHosts, ", ")},
+				{"Sticky", strconv.FormatBool(database.Sticky)},
+				{"Max idle conns", strconv.Itoa(database.MaxIdleConns)},
+				{"Max open conns", strconv.Itoa(database.MaxOpenConns)},
+				{"Conn max lifetime", database.ConnMaxLifetime.String()},
+				{"Conn max idle time", database.ConnMaxIdleTime.String()},
+				{"DSN", database.RedactedDSN()},
+			}
+			for _, row := range rows {
+				fmt.Printf("%-20s %s\n", row[0], row[1])
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"govel/config"
+	"time"
+)
+
+func DBWaitCommand(configuration config.Config) Command {
+	return Command{
+		Name:        "db:wait",
+		Description: "Wait until the database accepts connections, e.g. db:wait --timeout=60s --connection=analytics",
+		Handle: func(args []string) error {
+			timeout := 60 * time.Second
+			if value, ok := Option(args, "timeout"); ok {
+				parsed, err := time.ParseDuration(value)
+				if err != nil {
+					return errors.New("The timeout must be a duration like 60s, got \"" + value + "\".")
+				}
+				timeout = parsed
+			}
+			name := configuration.Get("database.default")
+			if value, ok := Option(args, "connection"); ok && value != "" {
+				name = value
+			}
+
+			database, err := config.LoadConnectionConfig(configuration, name)
+			if err != nil {
+				return err
+			}
+
+			start := time.Now()
+			connection, err := config.ConnectDatabase(database, timeout)
+			if err != nil {
+				return fmt.Errorf("Database %q is not ready after %s: %w", name, timeout, err)
+			}
+			if sqlDB, err := connection.DB(); err == nil {
+				sqlDB.Close()
+			}
+			fmt.Printf("Database %q is ready after %s.\n", name, time.Since(start).Round(time.Millisecond))
+			return nil
+		},
+	}
+}
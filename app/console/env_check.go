@@ -0,0 +1,43 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"govel/app/helper"
+	"govel/config"
+)
+
+func EnvCheckCommand(configuration config.Config) Command {
+	return Command{
+		Name:        "env:check",
+		Description: "Compare .env with .env.example and validate the settings",
+		Handle: func(args []string) error {
+			failed := false
+
+			// Compare the keys
+			missing, extra, err := config.CheckEnv(helper.BasePath(".env"), helper.BasePath(".env.example"))
+			if err != nil {
+				return err
+			}
+			for _, key := range missing {
+				fmt.Println("Missing in .env: " + key)
+				failed = true
+			}
+			for _, key := range extra {
+				fmt.Println("Not in .env.example: " + key)
+			}
+
+			// Validate the values
+			if _, err := config.Load(configuration); err != nil {
+				fmt.Println(err.Error())
+				failed = true
+			}
+
+			if failed {
+				return errors.New("Environment check failed.")
+			}
+			fmt.Println("Environment is valid.")
+			return nil
+		},
+	}
+}
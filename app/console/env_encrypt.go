@@ -0,0 +1,117 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"govel/app/encryption"
+	"govel/config"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+func EnvEncryptCommand(configuration config.Config) Command {
+	return Command{
+		Name:        "env:encrypt",
+		Description: "Encrypt the values of .env with APP_KEY, e.g. env:encrypt DB_PASSWORD, the secrets by default",
+		Handle: func(args []string) error {
+			encrypter := config.NewEncrypter(configuration)
+			if encrypter == nil {
+				return errors.New("APP_KEY is not set, run ./govel key:generate first.")
+			}
+
+			keys := Arguments(args)
+			return rewriteEnvFile(envFilename(args), func(key string, value string) (string, bool) {
+				if key == "APP_KEY" || value == "" || value == "null" || strings.HasPrefix(value, encryption.Prefix) {
+					return "", false
+				}
+				if len(keys) == 0 && !config.IsSecret(key) || len(keys) > 0 && !contains(keys, key) {
+					return "", false
+				}
+				return encryption.Prefix + encrypter.EncryptString(value), true
+			})
+		},
+	}
+}
+
+func EnvDecryptCommand(configuration config.Config) Command {
+	return Command{
+		Name:        "env:decrypt",
+		Description: "Decrypt the values of .env with APP_KEY, e.g. env:decrypt DB_PASSWORD, all by default",
+		Handle: func(args []string) error {
+			encrypter := config.NewEncrypter(configuration)
+			if encrypter == nil {
+				return errors.New("APP_KEY is not set.")
+			}
+
+			keys := Arguments(args)
+			var failed error
+			err := rewriteEnvFile(envFilename(args), func(key string, value string) (string, bool) {
+				if !strings.HasPrefix(value, encryption.Prefix) || len(keys) > 0 && !contains(keys, key) {
+					return "", false
+				}
+				decrypted, err := encrypter.DecryptString(strings.TrimPrefix(value, encryption.Prefix))
+				if err != nil {
+					failed = errors.New(key + " can't be decrypted with APP_KEY.")
+					return "", false
+				}
+				return decrypted, true
+			})
+			if err != nil {
+				return err
+			}
+			return failed
+		},
+	}
+}
+
+// Replace the values of the env file in place, the comments and the order
+// of the lines are kept
+func rewriteEnvFile(filename string, replace func(key string, value string) (string, bool)) error {
+	values, err := godotenv.Read(filename)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for key, value := range values {
+		replaced, ok := replace(key, value)
+		if !ok {
+			continue
+		}
+		pattern := regexp.MustCompile(`(?m)^(export\s+)?` + regexp.QuoteMeta(key) + `\s*=.*$`)
+		line := []byte(key + "=" + quoteEnv(replaced))
+		content = pattern.ReplaceAllFunc(content, func([]byte) []byte { return line })
+		fmt.Println("Updated " + key)
+		changed++
+	}
+
+	if changed == 0 {
+		fmt.Println("Nothing to update.")
+		return nil
+	}
+	return os.WriteFile(filename, content, 0600)
+}
+
+// Quote the value if it has spaces or special characters
+func quoteEnv(value string) string {
+	if strings.ContainsAny(value, " #\"'\\$\n\t") {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`).Replace(value) + `"`
+	}
+	return value
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
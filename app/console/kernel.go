@@ -0,0 +1,20 @@
+package console
+
+import (
+	"govel/config"
+)
+
+// Commands of the govel binary, e.g. ./govel env:check
+func Commands(configuration config.Config) []Command {
+	return []Command{
+		EnvCheckCommand(configuration),
+		ConfigCacheCommand(configuration),
+		ConfigClearCommand(),
+		ConfigShowCommand(configuration),
+		KeyGenerateCommand(),
+		EnvEncryptCommand(configuration),
+		EnvDecryptCommand(configuration),
+		DBShowCommand(configuration),
+		DBWaitCommand(configuration),
+	}
+}
@@ -0,0 +1,57 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"govel/app/encryption"
+	"govel/app/helper"
+	"os"
+	"regexp"
+	"strings"
+)
+
+func KeyGenerateCommand() Command {
+	return Command{
+		Name:        "key:generate",
+		Description: "Set APP_KEY of .env to a random key, --show to print only, --force to replace",
+		Handle: func(args []string) error {
+			key := encryption.GenerateKey()
+			if _, show := Option(args, "show"); show {
+				fmt.Println(key)
+				return nil
+			}
+
+			filename := envFilename(args)
+			content, err := os.ReadFile(filename)
+			if err != nil {
+				return err
+			}
+
+			// Replacing the key makes the encrypted values unreadable
+			pattern := regexp.MustCompile(`(?m)^APP_KEY=.*$`)
+			current := pattern.Find(content)
+			if _, force := Option(args, "force"); !force && current != nil && strings.TrimSpace(string(current)) != "APP_KEY=" {
+				return errors.New("APP_KEY is already set, the encrypted values can't be decrypted with a new key. Use --force to replace it.")
+			}
+
+			if current == nil {
+				content = append(content, []byte("\nAPP_KEY="+key+"\n")...)
+			} else {
+				content = pattern.ReplaceAll(content, []byte("APP_KEY="+key))
+			}
+			if err := os.WriteFile(filename, content, 0600); err != nil {
+				return err
+			}
+			fmt.Println("Application key set successfully.")
+			return nil
+		},
+	}
+}
+
+// The env file of the --file option, .env by default
+func envFilename(args []string) string {
+	if filename, ok := Option(args, "file"); ok && filename != "" {
+		return helper.BasePath(filename)
+	}
+	return helper.BasePath(".env")
+}
@@ -0,0 +1,41 @@
+package console
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"govel/app/lifecycle"
+	"govel/config"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Write the spec of the routes registered by the app made with makeApp, e.g.
+// bootstrap.Routes not opening the connections
+func OpenAPIExportCommand(configuration config.Config, makeApp func() (*fiber.App, *lifecycle.Lifecycle)) Command {
+	return Command{
+		Name:        "openapi:export",
+		Description: "Write the OpenAPI spec of the API routes to a file, e.g. openapi:export docs/openapi.json",
+		Handle: func(args []string) error {
+			path := "openapi.json"
+			if arguments := Arguments(args); len(arguments) > 0 {
+				path = arguments[0]
+			}
+
+			app, hooks := makeApp()
+			defer hooks.Shutdown(context.Background())
+
+			document := config.NewOpenAPIGenerator(configuration).Generate(app)
+			body, err := json.MarshalIndent(document, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, append(body, '\n'), 0644); err != nil {
+				return err
+			}
+			fmt.Printf("Exported %d paths to %s.\n", len(document.Paths), path)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,250 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Lifetime int
+
+const (
+	// One instance for the app
+	SingletonLifetime Lifetime = iota
+	// One instance for the scope, e.g. a request
+	ScopedLifetime
+	// New instance on every resolve
+	TransientLifetime
+)
+
+func (lifetime Lifetime) String() string {
+	return [...]string{"singleton", "scoped", "transient"}[lifetime]
+}
+
+type binding struct {
+	lifetime Lifetime
+	factory  func(c *Container) interface{}
+}
+
+// Container of the app services, the bindings are keyed by type
+type Container struct {
+	root      *Container
+	ctx       context.Context
+	bindings  map[reflect.Type]*binding
+	instances map[reflect.Type]interface{}
+	mutex     sync.Mutex
+}
+
+func New() *Container {
+	container := &Container{
+		ctx:       context.Background(),
+		bindings:  map[reflect.Type]*binding{},
+		instances: map[reflect.Type]interface{}{},
+	}
+	container.root = container
+	return container
+}
+
+// Make the scope of the request, the scoped bindings are resolved once per
+// scope and can use the context of the scope
+func (container *Container) Scope(ctx context.Context) *Container {
+	return &Container{
+		root:      container.root,
+		ctx:       ctx,
+		bindings:  container.root.bindings,
+		instances: map[reflect.Type]interface{}{},
+	}
+}
+
+// Context of the scope, the background context for the root
+func (container *Container) Context() context.Context {
+	return container.ctx
+}
+
+func (container *Container) IsScope() bool {
+	return container.root != container
+}
+
+// Bind the factory of the type, the binding of the same type is replaced
+func Bind[T any](container *Container, lifetime Lifetime, factory func(c *Container) T) {
+	root := container.root
+	root.mutex.Lock()
+	defer root.mutex.Unlock()
+
+	key := typeOf[T]()
+	root.bindings[key] = &binding{
+		lifetime: lifetime,
+		factory:  func(c *Container) interface{} { return factory(c) },
+	}
+	delete(root.instances, key)
+}
+
+func Singleton[T any](container *Container, factory func(c *Container) T) {
+	Bind(container, SingletonLifetime, factory)
+}
+
+func Scoped[T any](container *Container, factory func(c *Container) T) {
+	Bind(container, ScopedLifetime, factory)
+}
+
+func Transient[T any](container *Container, factory func(c *Container) T) {
+	Bind(container, TransientLifetime, factory)
+}
+
+// Bind the value as singleton
+func Instance[T any](container *Container, value T) {
+	Bind(container, SingletonLifetime, func(c *Container) T { return value })
+}
+
+// Replace the binding with the fake, e.g. in tests. The fake is shared by
+// every scope.
+func Swap[T any](container *Container, fake T) {
+	Instance(container, fake)
+}
+
+func Has[T any](container *Container) bool {
+	root := container.root
+	root.mutex.Lock()
+	defer root.mutex.Unlock()
+	_, ok := root.bindings[typeOf[T]()]
+	return ok
+}
+
+// Resolve the type, panic with MissingBindingError if not bound
+func Make[T any](container *Container) T {
+	return container.resolve(typeOf[T]()).(T)
+}
+
+// Resolve the type, return the error instead of panic
+func Resolve[T any](container *Container) (value T, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			resolveErr, ok := recovered.(error)
+			if !ok {
+				panic(recovered)
+			}
+			err = resolveErr
+		}
+	}()
+	return Make[T](container), nil
+}
+
+func (container *Container) resolve(key reflect.Type) interface{} {
+	root := container.root
+	root.mutex.Lock()
+	binding, ok := root.bindings[key]
+	root.mutex.Unlock()
+	if !ok {
+		panic(MissingBindingError{Type: key.String()})
+	}
+
+	switch binding.lifetime {
+	case SingletonLifetime:
+		return root.cached(key, func() interface{} { return binding.factory(root) })
+	case ScopedLifetime:
+		if !container.IsScope() {
+			panic(fmt.Errorf("%s is scoped and can't be resolved outside a scope", key))
+		}
+		return container.cached(key, func() interface{} { return binding.factory(container) })
+	}
+	return wrap(key, func() interface{} { return binding.factory(container) })
+}
+
+// Get the instance or make it, the factory runs without the lock so it can
+// resolve its own dependencies
+func (container *Container) cached(key reflect.Type, factory func() interface{}) interface{} {
+	container.mutex.Lock()
+	instance, ok := container.instances[key]
+	container.mutex.Unlock()
+	if ok {
+		return instance
+	}
+
+	instance = wrap(key, factory)
+	container.mutex.Lock()
+	defer container.mutex.Unlock()
+	if existing, ok := container.instances[key]; ok {
+		return existing
+	}
+	container.instances[key] = instance
+	return instance
+}
+
+// Add the type to the missing binding error, so the error tells which
+// binding needs it
+func wrap(key reflect.Type, factory func() interface{}) interface{} {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if missing, ok := recovered.(MissingBindingError); ok {
+				missing.RequiredBy = append(missing.RequiredBy, key.String())
+				panic(missing)
+			}
+			panic(recovered)
+		}
+	}()
+	return factory()
+}
+
+// Resolve every binding in a scope and report all missing bindings at once
+func (container *Container) Validate() error {
+	root := container.root
+	root.mutex.Lock()
+	keys := []reflect.Type{}
+	for key := range root.bindings {
+		keys = append(keys, key)
+	}
+	root.mutex.Unlock()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	scope := root.Scope(context.Background())
+	messages := []string{}
+	for _, key := range keys {
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					messages = append(messages, fmt.Sprint(recovered))
+				}
+			}()
+			scope.resolve(key)
+		}()
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("Invalid container bindings:\n- %s", strings.Join(unique(messages), "\n- "))
+	}
+	return nil
+}
+
+type MissingBindingError struct {
+	Type string
+	// The bindings needing the type, the nearest first
+	RequiredBy []string
+}
+
+func (err MissingBindingError) Error() string {
+	message := "No binding for " + err.Type
+	if len(err.RequiredBy) > 0 {
+		message += " required by " + strings.Join(err.RequiredBy, " <- ")
+	}
+	return message
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func unique(values []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,18 @@
+package encryption
+
+import "govel/app/exception"
+
+var defaultEncrypter Encrypter
+
+// Set the encrypter used by the entity columns, set on boot from APP_KEY
+func SetDefault(encrypter Encrypter) {
+	defaultEncrypter = encrypter
+}
+
+// Get the encrypter set on boot, panic if APP_KEY is not configured
+func Default() Encrypter {
+	if defaultEncrypter == nil {
+		exception.PanicIfNeeded("APP_KEY is required to encrypt the values, run ./govel key:generate")
+	}
+	return defaultEncrypter
+}
@@ -0,0 +1,131 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"govel/app/exception"
+	"strings"
+)
+
+// Prefix of the encrypted values in the env files
+const Prefix = "encrypted:"
+
+// Prefix of the APP_KEY encoded with base64
+const KeyPrefix = "base64:"
+
+var ErrInvalidPayload = errors.New("The payload is invalid.")
+
+type Encrypter interface {
+	// Encrypt the value, the payload is base64 of the nonce and ciphertext
+	Encrypt(value []byte) string
+
+	// Decrypt the payload, fail if the payload was modified or encrypted
+	// with other key
+	Decrypt(payload string) ([]byte, error)
+
+	EncryptString(value string) string
+	DecryptString(payload string) (string, error)
+
+	// Keyed hash of the value to find the encrypted values by exact match,
+	// the same value always has the same hash
+	BlindIndex(value string) string
+}
+
+type aesGCM struct {
+	aead     cipher.AEAD
+	indexKey []byte
+}
+
+// Make the AES-256-GCM encrypter, the key must be 32 bytes
+func New(key []byte) (Encrypter, error) {
+	if len(key) != 32 {
+		return nil, errors.New("The key must be 32 bytes, run ./govel key:generate to make one.")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Derive other key for the blind index, the encryption key is never used
+	// for hashing
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("blind-index"))
+	return &aesGCM{aead: aead, indexKey: mac.Sum(nil)}, nil
+}
+
+// Make the encrypter from the APP_KEY like "base64:..."
+func FromAppKey(appKey string) (Encrypter, error) {
+	key, err := ParseKey(appKey)
+	if err != nil {
+		return nil, err
+	}
+	return New(key)
+}
+
+// Decode the APP_KEY, the key without "base64:" prefix is used as is
+func ParseKey(appKey string) ([]byte, error) {
+	if strings.HasPrefix(appKey, KeyPrefix) {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(appKey, KeyPrefix))
+		if err != nil {
+			return nil, errors.New("The key is not valid base64.")
+		}
+		return key, nil
+	}
+	return []byte(appKey), nil
+}
+
+// Generate a random 32 bytes key encoded as "base64:..."
+func GenerateKey() string {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	exception.PanicIfNeeded(err)
+	return KeyPrefix + base64.StdEncoding.EncodeToString(key)
+}
+
+func (encrypter *aesGCM) Encrypt(value []byte) string {
+	nonce := make([]byte, encrypter.aead.NonceSize())
+	_, err := rand.Read(nonce)
+	exception.PanicIfNeeded(err)
+
+	sealed := encrypter.aead.Seal(nonce, nonce, value, nil)
+	return base64.StdEncoding.EncodeToString(sealed)
+}
+
+func (encrypter *aesGCM) Decrypt(payload string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < encrypter.aead.NonceSize() {
+		return nil, ErrInvalidPayload
+	}
+
+	size := encrypter.aead.NonceSize()
+	value, err := encrypter.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	return value, nil
+}
+
+func (encrypter *aesGCM) EncryptString(value string) string {
+	return encrypter.Encrypt([]byte(value))
+}
+
+func (encrypter *aesGCM) DecryptString(payload string) (string, error) {
+	value, err := encrypter.Decrypt(payload)
+	return string(value), err
+}
+
+func (encrypter *aesGCM) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, encrypter.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"time"
+)
+
+type LoginAttempt struct {
+	ID         uint      `gorm:"primaryKey"`
+	Email      string    `gorm:"type:varchar(255);index;not null"`
+	Ip         string    `gorm:"type:varchar(45);index;not null"`
+	UserAgent  string    `gorm:"type:varchar(255);default:null"`
+	Successful bool      `gorm:"index;not null"`
+	CreatedAt  time.Time `gorm:"index"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+)
+
+type PersonalAccessToken struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"index;not null"`
+	Name       string `gorm:"type:varchar(255);not null"`
+	Token      string `gorm:"type:varchar(64);unique;not null"`
+	Abilities  string `gorm:"type:text"`
+	LastUsedAt *time.Time
+	ExpiresAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,9 @@
+package entity
+
+type Session struct {
+	ID           string `gorm:"type:varchar(64);primaryKey"`
+	UserID       *uint  `gorm:"index"`
+	Payload      string `gorm:"type:text;not null"`
+	LastActivity int64  `gorm:"index;not null"`
+	ExpiredAt    int64  `gorm:"index;not null"`
+}
@@ -0,0 +1,59 @@
+package exception
+
+import (
+	"fmt"
+	"govel/app/model"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Queries of the request saved to the fiber context as "queries", e.g. by
+// the InspectQueries middleware, the failed SQL is shown in the response
+type FailedQueries interface {
+	FailedSQL() string
+}
+
+// Headers and fields hidden from the debug response
+var sensitive = []string{"authorization", "cookie", "password", "token", "secret", "csrf", "key"}
+
+func debugError(ctx *fiber.Ctx, err error, stack string) model.DebugError {
+	result := model.DebugError{
+		Message: err.Error(),
+		Type:    fmt.Sprintf("%T", err),
+		Request: model.DebugRequest{
+			Method:  ctx.Method(),
+			URL:     ctx.OriginalURL(),
+			Headers: map[string]string{},
+			Query:   map[string]string{},
+			Form:    map[string]string{},
+		},
+	}
+	if stack != "" {
+		result.Stack = strings.Split(strings.ReplaceAll(stack, "\t", "    "), "\n")
+	}
+	if queries, ok := ctx.Locals("queries").(FailedQueries); ok {
+		result.SQL = queries.FailedSQL()
+	}
+
+	ctx.Request().Header.VisitAll(func(key []byte, value []byte) {
+		result.Request.Headers[string(key)] = redact(string(key), string(value))
+	})
+	ctx.Request().URI().QueryArgs().VisitAll(func(key []byte, value []byte) {
+		result.Request.Query[string(key)] = redact(string(key), string(value))
+	})
+	ctx.Request().PostArgs().VisitAll(func(key []byte, value []byte) {
+		result.Request.Form[string(key)] = redact(string(key), string(value))
+	})
+	return result
+}
+
+func redact(key string, value string) string {
+	key = strings.ToLower(key)
+	for _, word := range sensitive {
+		if strings.Contains(key, word) {
+			return "[hidden]"
+		}
+	}
+	return value
+}
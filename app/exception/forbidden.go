@@ -0,0 +1,10 @@
+package exception
+
+// The user is authenticated but not allowed to do it, responded with 403
+type ForbiddenError struct {
+	Message string
+}
+
+func (forbiddenError ForbiddenError) Error() string {
+	return forbiddenError.Message
+}
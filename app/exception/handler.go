@@ -1,24 +1,106 @@
 package exception
 
 import (
-	"govel/app/model"
+	"govel/app/requestid"
+	"log/slog"
+	"math"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 )
 
+// Respond the error with the renderer set by SetRenderer
 func ErrorHandler(ctx *fiber.Ctx, err error) error {
-	_, ok := err.(ValidationError)
+	return CurrentRenderer().Render(ctx, makeProblem(ctx, err))
+}
+
+func makeProblem(ctx *fiber.Ctx, err error) Problem {
+	requestID := requestid.FromContext(ctx.UserContext())
+
+	validationError, ok := err.(ValidationError)
+	if ok {
+		return Problem{
+			Status:    400,
+			Type:      "validation-error",
+			Detail:    err.Error(),
+			Errors:    validationError.Fields,
+			RequestID: requestID,
+		}
+	}
+
+	tooManyRequestsError, ok := err.(TooManyRequestsError)
+	if ok {
+		ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(tooManyRequestsError.RetryAfter.Seconds()))))
+		return Problem{
+			Status:    429,
+			Type:      "too-many-requests",
+			Detail:    err.Error(),
+			RequestID: requestID,
+		}
+	}
+
+	forbiddenError, ok := err.(ForbiddenError)
 	if ok {
-		return ctx.Status(400).JSON(model.WebResponse{
-			Code:    400,
-			Message: "BAD_REQUEST",
-			Data:    err.Error(),
-		})
+		return Problem{
+			Status:    403,
+			Type:      "forbidden",
+			Detail:    forbiddenError.Message,
+			RequestID: requestID,
+		}
 	}
 
-	return ctx.Status(500).JSON(model.WebResponse{
-		Code:    500,
-		Message: "INTERNAL_SERVER_ERROR",
-		Data:    err.Error(),
+	// Error with status code from fiber or middleware
+	fiberError, ok := err.(*fiber.Error)
+	if ok {
+		return Problem{
+			Status:    fiberError.Code,
+			Type:      strings.ToLower(strings.ReplaceAll(utils.StatusMessage(fiberError.Code), " ", "-")),
+			Detail:    fiberError.Message,
+			RequestID: requestID,
+		}
+	}
+
+	// Log and report the unexpected error with the stack of the panic
+	stack, _ := ctx.Locals("stack").(string)
+	errorID := requestid.New()
+	slog.ErrorContext(ctx.UserContext(), err.Error(),
+		slog.String("error_id", errorID),
+		slog.String("method", ctx.Method()),
+		slog.String("path", ctx.Path()),
+		slog.String("stack", stack),
+	)
+	report(ctx.UserContext(), Report{
+		ID:        errorID,
+		Err:       err,
+		Stack:     stack,
+		Method:    ctx.Method(),
+		Path:      ctx.Path(),
+		RequestID: requestID,
+		Time:      time.Now(),
 	})
+
+	// Hide the internals like the SQL unless debugging
+	problem := Problem{
+		Status:    500,
+		Type:      "internal-server-error",
+		Detail:    "Internal server error, report the error ID to find it in the logs.",
+		RequestID: requestID,
+		ErrorID:   errorID,
+	}
+	if Debug() {
+		debug := debugError(ctx, err, stack)
+		problem.Detail = err.Error()
+		problem.Debug = &debug
+	}
+	return problem
+}
+
+// Save the stack of the panic to log it with the error, use it as the stack
+// trace handler of the recover middleware
+func SaveStack(ctx *fiber.Ctx, recovered interface{}) {
+	ctx.Locals("stack", string(debug.Stack()))
 }
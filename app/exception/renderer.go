@@ -0,0 +1,113 @@
+package exception
+
+import (
+	"govel/app/model"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const ProblemContentType = "application/problem+json"
+
+var renderer Renderer = EnvelopeRenderer{}
+
+// Set the renderer of the error responses, the envelope by default
+func SetRenderer(errorRenderer Renderer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	renderer = errorRenderer
+}
+
+func CurrentRenderer() Renderer {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return renderer
+}
+
+// Error to respond, made by ErrorHandler
+type Problem struct {
+	Status int
+	// Short name of the error like "validation-error"
+	Type   string
+	Detail string
+	// Messages of the invalid fields
+	Errors    map[string]string
+	RequestID string
+	ErrorID   string
+	// Details of the unexpected error if debugging
+	Debug *model.DebugError
+}
+
+// Code of the status like "BAD_REQUEST"
+func (problem Problem) Code() string {
+	return strings.ToUpper(strings.ReplaceAll(problem.Title(), " ", "_"))
+}
+
+func (problem Problem) Title() string {
+	return utils.StatusMessage(problem.Status)
+}
+
+// Respond the error in a format
+type Renderer interface {
+	Render(ctx *fiber.Ctx, problem Problem) error
+}
+
+// Respond the model.WebResponse envelope with the code, message and data
+type EnvelopeRenderer struct{}
+
+func (renderer EnvelopeRenderer) Render(ctx *fiber.Ctx, problem Problem) error {
+	var data interface{} = problem.Detail
+	if problem.Debug != nil {
+		data = problem.Debug
+	}
+	return ctx.Status(problem.Status).JSON(model.WebResponse{
+		Code:      problem.Status,
+		Message:   problem.Code(),
+		Data:      data,
+		RequestID: problem.RequestID,
+		ErrorID:   problem.ErrorID,
+	})
+}
+
+// Respond application/problem+json of RFC 7807
+type ProblemRenderer struct {
+	// Base of the type URIs like "https://example.com/problems", the type
+	// is "about:blank" if empty
+	TypeBaseURL string
+}
+
+func (renderer ProblemRenderer) Render(ctx *fiber.Ctx, problem Problem) error {
+	problemType := "about:blank"
+	if renderer.TypeBaseURL != "" && problem.Type != "" {
+		problemType = strings.TrimSuffix(renderer.TypeBaseURL, "/") + "/" + problem.Type
+	}
+
+	ctx.Status(problem.Status)
+	err := ctx.JSON(model.ProblemResponse{
+		Type:      problemType,
+		Title:     problem.Title(),
+		Status:    problem.Status,
+		Detail:    problem.Detail,
+		Instance:  ctx.OriginalURL(),
+		Errors:    problem.Errors,
+		RequestID: problem.RequestID,
+		ErrorID:   problem.ErrorID,
+		Debug:     problem.Debug,
+	})
+	ctx.Set(fiber.HeaderContentType, ProblemContentType)
+	return err
+}
+
+// Respond problem+json if the client accepts it, otherwise the default
+type NegotiatingRenderer struct {
+	Default Renderer
+	Problem Renderer
+}
+
+func (renderer NegotiatingRenderer) Render(ctx *fiber.Ctx, problem Problem) error {
+	if strings.Contains(ctx.Get(fiber.HeaderAccept), ProblemContentType) {
+		return renderer.Problem.Render(ctx, problem)
+	}
+	return renderer.Default.Render(ctx, problem)
+}
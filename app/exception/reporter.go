@@ -0,0 +1,96 @@
+package exception
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Unexpected error responded with 500
+type Report struct {
+	// Error ID responded to the client and logged
+	ID        string
+	Err       error
+	Stack     string
+	Method    string
+	Path      string
+	RequestID string
+	Time      time.Time
+}
+
+// Send the unexpected errors to an error tracker. Report is called in the
+// request, send the report in the background if it's slow.
+type Reporter interface {
+	Report(ctx context.Context, report Report)
+}
+
+// Adapt a function to Reporter
+type ReporterFunc func(ctx context.Context, report Report)
+
+func (fn ReporterFunc) Report(ctx context.Context, report Report) {
+	fn(ctx, report)
+}
+
+// Reporter added by AddReporter, the pointer identifies it on removal
+type registeredReporter struct {
+	Reporter
+}
+
+var (
+	reporters []*registeredReporter
+	debugMode bool
+	mutex     sync.RWMutex
+)
+
+// Add the reporter of the unexpected errors, call remove to stop reporting
+// to it, e.g. at the end of a test
+func AddReporter(reporter Reporter) (remove func()) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	registered := &registeredReporter{reporter}
+	reporters = append(reporters, registered)
+
+	return func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for i, item := range reporters {
+			if item == registered {
+				reporters = append(reporters[:i:i], reporters[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Respond the details of the unexpected errors, e.g. the stack and the SQL,
+// set from APP_DEBUG. Never enable it on production.
+func SetDebug(debug bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	debugMode = debug
+}
+
+func Debug() bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return debugMode
+}
+
+// Send the report to every reporter, the panic of a reporter is logged
+func report(ctx context.Context, report Report) {
+	mutex.RLock()
+	items := append([]*registeredReporter{}, reporters...)
+	mutex.RUnlock()
+
+	for _, reporter := range items {
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					slog.ErrorContext(ctx, "Error reporter failed", slog.Any("panic", recovered))
+				}
+			}()
+			reporter.Report(ctx, report)
+		}()
+	}
+}
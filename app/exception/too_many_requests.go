@@ -0,0 +1,12 @@
+package exception
+
+import "time"
+
+type TooManyRequestsError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (tooManyRequestsError TooManyRequestsError) Error() string {
+	return tooManyRequestsError.Message
+}
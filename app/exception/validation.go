@@ -1,9 +1,31 @@
 package exception
 
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
 type ValidationError struct {
 	Message string
+	// Messages of the invalid fields by the field name
+	Fields map[string]string
 }
 
 func (validationError ValidationError) Error() string {
 	return validationError.Message
 }
+
+// Make the error from the ozzo validation error, the invalid fields are kept
+func NewValidationError(err error) ValidationError {
+	validationError := ValidationError{Message: err.Error()}
+
+	fields := validation.Errors{}
+	if errors.As(err, &fields) {
+		validationError.Fields = map[string]string{}
+		for field, fieldErr := range fields {
+			validationError.Fields[field] = fieldErr.Error()
+		}
+	}
+	return validationError
+}
@@ -0,0 +1,90 @@
+package hashing
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"govel/app/exception"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+type Argon2idOptions struct {
+	// Memory in KiB
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+)
+
+type argon2idHasher struct {
+	options Argon2idOptions
+}
+
+func NewArgon2idHasher(options Argon2idOptions) Hasher {
+	return &argon2idHasher{
+		options: options,
+	}
+}
+
+// Hash with PHC string format, e.g. $argon2id$v=19$m=65536,t=4,p=1$salt$key
+func (hasher *argon2idHasher) Make(value string) string {
+	salt := make([]byte, argon2idSaltLength)
+	_, err := rand.Read(salt)
+	exception.PanicIfNeeded(err)
+
+	key := argon2.IDKey([]byte(value), salt, hasher.options.Time, hasher.options.Memory, hasher.options.Threads, argon2idKeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		hasher.options.Memory,
+		hasher.options.Time,
+		hasher.options.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func (hasher *argon2idHasher) Check(value string, hashed string) bool {
+	options, salt, key, ok := decodeArgon2id(hashed)
+	if !ok {
+		return false
+	}
+	other := argon2.IDKey([]byte(value), salt, options.Time, options.Memory, options.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+func (hasher *argon2idHasher) NeedsRehash(hashed string) bool {
+	options, _, _, ok := decodeArgon2id(hashed)
+	return !ok || options != hasher.options
+}
+
+func decodeArgon2id(hashed string) (options Argon2idOptions, salt []byte, key []byte, ok bool) {
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return options, nil, nil, false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return options, nil, nil, false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &options.Memory, &options.Time, &options.Threads); err != nil {
+		return options, nil, nil, false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return options, nil, nil, false
+	}
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return options, nil, nil, false
+	}
+	return options, salt, key, true
+}
@@ -0,0 +1,32 @@
+package hashing
+
+import (
+	"govel/app/exception"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type bcryptHasher struct {
+	rounds int
+}
+
+func NewBcryptHasher(rounds int) Hasher {
+	return &bcryptHasher{
+		rounds: rounds,
+	}
+}
+
+func (hasher *bcryptHasher) Make(value string) string {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(value), hasher.rounds)
+	exception.PanicIfNeeded(err)
+	return string(hashed)
+}
+
+func (hasher *bcryptHasher) Check(value string, hashed string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(value)) == nil
+}
+
+func (hasher *bcryptHasher) NeedsRehash(hashed string) bool {
+	cost, err := bcrypt.Cost([]byte(hashed))
+	return err != nil || cost != hasher.rounds
+}
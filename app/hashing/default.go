@@ -0,0 +1,18 @@
+package hashing
+
+import "govel/app/exception"
+
+var defaultHasher Hasher
+
+// Set the hasher used by the entity columns, set on boot from the config
+func SetDefault(hasher Hasher) {
+	defaultHasher = hasher
+}
+
+// Get the hasher set on boot
+func Default() Hasher {
+	if defaultHasher == nil {
+		exception.PanicIfNeeded("The default hasher is not set")
+	}
+	return defaultHasher
+}
@@ -0,0 +1,12 @@
+package hashing
+
+import "govel/app/helper"
+
+// Hash of a random value, checked instead of the hash of a missing user so
+// the check takes the same time as a wrong password. Make it once on boot,
+// making it on the request would take longer than the check alone.
+type DummyHash string
+
+func NewDummyHash(hasher Hasher) DummyHash {
+	return DummyHash(hasher.Make(helper.RandomString(40)))
+}
@@ -0,0 +1,61 @@
+package hashing
+
+import "strings"
+
+type Hasher interface {
+	// Hash the value
+	Make(value string) string
+
+	// Check the value match the hash
+	Check(value string, hashed string) bool
+
+	// Check the hash was made with other algorithm or options
+	NeedsRehash(hashed string) bool
+}
+
+type manager struct {
+	driver  string
+	drivers map[string]Hasher
+}
+
+// Make hashes with the driver and check hashes of every supported algorithm,
+// so the old hashes keep working after the driver changed
+func New(driver string, bcrypt Hasher, argon2id Hasher) Hasher {
+	return &manager{
+		driver: driver,
+		drivers: map[string]Hasher{
+			"bcrypt":   bcrypt,
+			"argon2id": argon2id,
+		},
+	}
+}
+
+func (manager *manager) Make(value string) string {
+	return manager.drivers[manager.driver].Make(value)
+}
+
+func (manager *manager) Check(value string, hashed string) bool {
+	hasher, ok := manager.drivers[Algorithm(hashed)]
+	if !ok {
+		return false
+	}
+	return hasher.Check(value, hashed)
+}
+
+func (manager *manager) NeedsRehash(hashed string) bool {
+	if Algorithm(hashed) != manager.driver {
+		return true
+	}
+	return manager.drivers[manager.driver].NeedsRehash(hashed)
+}
+
+// Detect the algorithm from the hash prefix
+func Algorithm(hashed string) string {
+	switch {
+	case strings.HasPrefix(hashed, "$argon2id$"):
+		return "argon2id"
+	case strings.HasPrefix(hashed, "$2a$"), strings.HasPrefix(hashed, "$2b$"), strings.HasPrefix(hashed, "$2y$"):
+		return "bcrypt"
+	}
+	return ""
+}
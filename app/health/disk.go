@@ -0,0 +1,26 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Check the directory is writable and has the free space in bytes
+func CheckDisk(ctx context.Context, path string, minFree uint64) error {
+	file, err := os.CreateTemp(path, ".health-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	free, err := freeSpace(path)
+	if err != nil {
+		return err
+	}
+	if free < minFree {
+		return fmt.Errorf("%d MB free, %d MB required", free>>20, minFree>>20)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package health
+
+import "syscall"
+
+func freeSpace(path string) (uint64, error) {
+	stat := syscall.Statfs_t{}
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
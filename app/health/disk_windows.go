@@ -0,0 +1,23 @@
+//go:build windows
+
+package health
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func freeSpace(path string) (uint64, error) {
+	kernel := syscall.NewLazyDLL("kernel32.dll")
+	pointer, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var free uint64
+	result, _, err := kernel.NewProc("GetDiskFreeSpaceExW").Call(uintptr(unsafe.Pointer(pointer)), uintptr(unsafe.Pointer(&free)), 0, 0)
+	if result == 0 {
+		return 0, err
+	}
+	return free, nil
+}
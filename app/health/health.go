@@ -0,0 +1,163 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Liveness checks fail only if the process must be restarted, readiness
+// checks fail while the app can't serve the requests
+const (
+	Liveness  = "liveness"
+	Readiness = "readiness"
+)
+
+type Check struct {
+	Name string
+	// Liveness or Readiness, Readiness by default
+	Kind string
+	// The default timeout of the registry is used if zero
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+	// Extra information of the result, optional
+	Details func() interface{}
+}
+
+type Result struct {
+	Name      string      `json:"name"`
+	Status    string      `json:"status"`
+	Error     string      `json:"error,omitempty"`
+	LatencyMs float64     `json:"latency_ms"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+func (report Report) OK() bool {
+	return report.Status == StatusOK
+}
+
+// Report without the errors and the details of the checks, they may have
+// the hosts, the addresses and the pool stats
+func (report Report) Summary() Report {
+	summary := Report{Status: report.Status, Checks: []Result{}}
+	for _, result := range report.Checks {
+		summary.Checks = append(summary.Checks, Result{Name: result.Name, Status: result.Status, LatencyMs: result.LatencyMs})
+	}
+	return summary
+}
+
+type Registry struct {
+	timeout time.Duration
+	checks  []Check
+	mutex   sync.RWMutex
+}
+
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Add the check, the check with the same name is replaced
+func (registry *Registry) Register(check Check) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if check.Kind == "" {
+		check.Kind = Readiness
+	}
+	for i, registered := range registry.checks {
+		if registered.Name == check.Name {
+			registry.checks[i] = check
+			return
+		}
+	}
+	registry.checks = append(registry.checks, check)
+}
+
+// Run the checks of the kind at once, the readiness report includes the
+// liveness checks
+func (registry *Registry) Run(ctx context.Context, kind string) Report {
+	registry.mutex.RLock()
+	checks := []Check{}
+	for _, check := range registry.checks {
+		if check.Kind == kind || kind == Readiness {
+			checks = append(checks, check)
+		}
+	}
+	registry.mutex.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wait sync.WaitGroup
+	for i, check := range checks {
+		wait.Add(1)
+		go func(i int, check Check) {
+			defer wait.Done()
+			results[i] = registry.run(ctx, check)
+		}(i, check)
+	}
+	wait.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	report := Report{Status: StatusOK, Checks: results}
+	for _, result := range results {
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func (registry *Registry) run(ctx context.Context, check Check) Result {
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = registry.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Don't wait for the check that ignores the context
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- errors.New("check panicked")
+			}
+		}()
+		done <- check.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = errors.New("timed out after " + timeout.String())
+	}
+
+	result := Result{
+		Name:      check.Name,
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	if check.Details != nil {
+		result.Details = check.Details()
+	}
+	return result
+}
@@ -0,0 +1,14 @@
+package health
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Ping the redis server, authenticate and select the database first if set
+func PingRedis(ctx context.Context, address string, password string, database int) error {
+	client := redis.NewClient(&redis.Options{Addr: address, Password: password, DB: database})
+	defer client.Close()
+	return client.Ping(ctx).Err()
+}
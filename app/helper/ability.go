@@ -0,0 +1,17 @@
+package helper
+
+import "strings"
+
+// Check the granted abilities cover the ability, "*" covers every ability
+// and "users:*" covers the abilities like "users:read"
+func HasAbility(abilities []string, ability string) bool {
+	for _, granted := range abilities {
+		if granted == "*" || granted == ability {
+			return true
+		}
+		if strings.HasSuffix(granted, ":*") && strings.HasPrefix(ability, strings.TrimSuffix(granted, "*")) {
+			return true
+		}
+	}
+	return false
+}
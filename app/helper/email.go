@@ -0,0 +1,11 @@
+package helper
+
+import (
+	"strings"
+)
+
+// Normalize the email to compare it case and whitespace insensitively, e.g.
+// by the blind index or the login throttle
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -3,15 +3,63 @@ package helper
 import (
 	"crypto/ecdsa"
 	"crypto/rsa"
+	"errors"
 	"govel/app/exception"
 	"os"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// Key files set from the config, the env is read if not configured
+var privateKeyFile, publicKeyFile string
+
+// Set the key files used to sign and parse the tokens
+func ConfigureJWTKeys(privateKey string, publicKey string) {
+	privateKeyFile = privateKey
+	publicKeyFile = publicKey
+}
+
+func privateKeyPath() string {
+	if privateKeyFile == "" {
+		return BasePath(os.Getenv("PRIVATE_KEY_FILE"))
+	}
+	return BasePath(privateKeyFile)
+}
+
+func publicKeyPath() string {
+	if publicKeyFile == "" {
+		return BasePath(os.Getenv("PUBLIC_KEY_FILE"))
+	}
+	return BasePath(publicKeyFile)
+}
+
+// Check the key files are readable and hold EC or RSA keys
+func CheckJWTKeys() error {
+	privateKey, err := os.ReadFile(privateKeyPath())
+	if err != nil {
+		return err
+	}
+	if _, err := jwt.ParseECPrivateKeyFromPEM(privateKey); err != nil {
+		if _, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey); err != nil {
+			return errors.New("The private key is not an EC or RSA key")
+		}
+	}
+
+	publicKey, err := os.ReadFile(publicKeyPath())
+	if err != nil {
+		return err
+	}
+	if _, err := jwt.ParseECPublicKeyFromPEM(publicKey); err != nil {
+		if _, err := jwt.ParseRSAPublicKeyFromPEM(publicKey); err != nil {
+			return errors.New("The public key is not an EC or RSA key")
+		}
+	}
+	return nil
+}
+
 func MakeECDSAToken(c jwt.Claims, method jwt.SigningMethod) string {
 	token := jwt.NewWithClaims(method, c)
-	key := loadECPrivateKeyFromDisk(os.Getenv("PRIVATE_KEY_FILE"))
+	key := loadECPrivateKeyFromDisk(privateKeyPath())
 	signed, err := token.SignedString(key)
 	exception.PanicIfNeeded(err)
 	return signed
@@ -19,14 +67,14 @@ func MakeECDSAToken(c jwt.Claims, method jwt.SigningMethod) string {
 
 func MakeRSAToken(c jwt.Claims, method jwt.SigningMethod) string {
 	token := jwt.NewWithClaims(method, c)
-	key := loadRSAPrivateKeyFromDisk(os.Getenv("PRIVATE_KEY_FILE"))
+	key := loadRSAPrivateKeyFromDisk(privateKeyPath())
 	signed, err := token.SignedString(key)
 	exception.PanicIfNeeded(err)
 	return signed
 }
 
 func ParseECDSAToken(token string, method jwt.SigningMethod) *jwt.Token {
-	key := loadECPublicKeyFromDisk(os.Getenv("PUBLIC_KEY_FILE"))
+	key := loadECPublicKeyFromDisk(publicKeyPath())
 	jwtToken, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) {
 		return key, nil
 	})
@@ -35,7 +83,7 @@ func ParseECDSAToken(token string, method jwt.SigningMethod) *jwt.Token {
 }
 
 func ParseRSAToken(token string, method jwt.SigningMethod) *jwt.Token {
-	key := loadRSAPublicKeyFromDisk(os.Getenv("PUBLIC_KEY_FILE"))
+	key := loadRSAPublicKeyFromDisk(publicKeyPath())
 	jwtToken, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) {
 		return key, nil
 	})
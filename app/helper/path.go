@@ -0,0 +1,48 @@
+package helper
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var basePath struct {
+	once sync.Once
+	path string
+}
+
+// Get the absolute path from the project root. The root is APP_BASE_PATH if
+// set, otherwise the nearest directory with go.mod from the working
+// directory, so tests in sub directories find the same files. The working
+// directory is used when there is no go.mod, e.g. the deployed binary.
+func BasePath(paths ...string) string {
+	basePath.once.Do(func() {
+		basePath.path = findBasePath()
+	})
+	if len(paths) == 1 && filepath.IsAbs(paths[0]) {
+		return paths[0]
+	}
+	return filepath.Join(append([]string{basePath.path}, paths...)...)
+}
+
+func findBasePath() string {
+	if path := os.Getenv("APP_BASE_PATH"); path != "" {
+		absolute, err := filepath.Abs(path)
+		if err == nil {
+			return absolute
+		}
+	}
+
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	for directory := workingDirectory; ; directory = filepath.Dir(directory) {
+		if _, err := os.Stat(filepath.Join(directory, "go.mod")); err == nil {
+			return directory
+		}
+		if filepath.Dir(directory) == directory {
+			return workingDirectory
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"govel/app/exception"
+	"math/big"
+)
+
+const randomAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Generate a cryptographically secure alphanumeric string
+func RandomString(length int) string {
+	result := make([]byte, length)
+	max := big.NewInt(int64(len(randomAlphabet)))
+	for i := range result {
+		n, err := rand.Int(rand.Reader, max)
+		exception.PanicIfNeeded(err)
+		result[i] = randomAlphabet[n.Int64()]
+	}
+	return string(result)
+}
+
+// Hash the value with sha256 and return the hex string
+func SHA256(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,93 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"govel/app/exception"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP settings compatible with the common authenticator apps, RFC 6238
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30
+	TOTPSkew   = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Generate random base32 TOTP secret
+func TOTPGenerateSecret() string {
+	secret := make([]byte, 20)
+	_, err := rand.Read(secret)
+	exception.PanicIfNeeded(err)
+	return totpEncoding.EncodeToString(secret)
+}
+
+// Generate the TOTP code of the secret at the time
+func TOTPCode(secret string, at time.Time) string {
+	return totpCodeAt(secret, uint64(at.Unix()/TOTPPeriod))
+}
+
+// Validate the TOTP code at the time, the previous and next periods are
+// accepted to tolerate clock drift
+func TOTPValidate(secret string, code string, at time.Time) bool {
+	_, ok := TOTPVerify(secret, code, at, -1)
+	return ok
+}
+
+// Validate the TOTP code like TOTPValidate and return its time step, the
+// steps up to the last used step are rejected so a code can't be replayed
+func TOTPVerify(secret string, code string, at time.Time, lastStep int64) (step int64, ok bool) {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+	counter := at.Unix() / TOTPPeriod
+	for i := -TOTPSkew; i <= TOTPSkew; i++ {
+		step = counter + int64(i)
+		if step > lastStep && subtle.ConstantTimeCompare([]byte(totpCodeAt(secret, uint64(step))), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// Build the otpauth URI to be shown as QR code by the authenticator apps
+func TOTPURI(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TOTPDigits))
+	query.Set("period", fmt.Sprint(TOTPPeriod))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+func totpCodeAt(secret string, counter uint64) string {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	exception.PanicIfNeeded(err)
+
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation, RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulo := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", TOTPDigits, value%modulo)
+}
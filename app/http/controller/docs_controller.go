@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"encoding/json"
+	"govel/app/openapi"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DocsController struct {
+	generator *openapi.Generator
+	title     string
+	path      string
+	assetsURL string
+	spec      *generatedSpec
+}
+
+// Spec generated once on the first request, after every route is registered
+type generatedSpec struct {
+	once sync.Once
+	body []byte
+	err  error
+}
+
+func NewDocsController(generator *openapi.Generator, title string, path string, assetsURL string) DocsController {
+	return DocsController{generator: generator, title: title, path: path, assetsURL: assetsURL, spec: &generatedSpec{}}
+}
+
+func (controller *DocsController) Route(route fiber.Router) {
+	route.Get(controller.path, controller.UI).Name("docs")
+	route.Get(controller.path+"/openapi.json", controller.Spec).Name("docs.spec")
+}
+
+// Respond the Swagger UI of the spec
+func (controller *DocsController) UI(c *fiber.Ctx) error {
+	c.Type("html", "utf-8")
+	return openapi.SwaggerUI(c, controller.title, controller.path+"/openapi.json", controller.assetsURL)
+}
+
+// Respond the OpenAPI spec of the API routes
+func (controller *DocsController) Spec(c *fiber.Ctx) error {
+	controller.spec.once.Do(func() {
+		controller.spec.body, controller.spec.err = json.Marshal(controller.generator.Generate(c.App()))
+	})
+	if controller.spec.err != nil {
+		return controller.spec.err
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(controller.spec.body)
+}
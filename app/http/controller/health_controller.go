@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"govel/app/container"
+	"govel/app/health"
+	"govel/app/http/middleware"
+	"govel/app/lifecycle"
+	"govel/app/model"
+	"govel/app/requestid"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HealthController struct {
+	// Respond the errors and the details of the checks to every request
+	debug bool
+	// Bearer token of the requests seeing the errors and the details
+	token string
+}
+
+func NewHealthController(debug bool, token string) HealthController {
+	return HealthController{debug: debug, token: token}
+}
+
+func (controller *HealthController) Route(route fiber.Router) {
+	route.Get("/healthz", controller.Live).Name("healthz")
+	route.Get("/readyz", controller.Ready).Name("readyz")
+}
+
+// Alive unless a liveness check failed
+func (controller *HealthController) Live(c *fiber.Ctx) error {
+	registry := container.Make[*health.Registry](middleware.Scope(c))
+	return controller.respond(c, registry.Run(c.UserContext(), health.Liveness))
+}
+
+// Ready if every check passed and the app isn't shutting down, so the load
+// balancer stops sending the requests while they are drained
+func (controller *HealthController) Ready(c *fiber.Ctx) error {
+	scope := middleware.Scope(c)
+	if container.Make[*lifecycle.Lifecycle](scope).ShuttingDown() {
+		return controller.respond(c, health.Report{Status: health.StatusFail, Checks: []health.Result{
+			{Name: "shutdown", Status: health.StatusFail, Error: "the app is shutting down"},
+		}})
+	}
+
+	registry := container.Make[*health.Registry](scope)
+	return controller.respond(c, registry.Run(c.UserContext(), health.Readiness))
+}
+
+// Respond the status of the checks only unless debugging or the request has
+// the token, the errors may have the internal hosts and addresses
+func (controller *HealthController) respond(c *fiber.Ctx, report health.Report) error {
+	detailed := controller.debug || controller.token != "" &&
+		subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), []byte("Bearer "+controller.token)) == 1
+	if !detailed {
+		report = report.Summary()
+	}
+
+	if !report.OK() {
+		return c.Status(503).JSON(model.WebResponse{
+			Code:      503,
+			Message:   "SERVICE_UNAVAILABLE",
+			Data:      report,
+			RequestID: requestid.FromContext(c.UserContext()),
+		})
+	}
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    report,
+	})
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"fmt"
+	"govel/app/container"
+	"govel/app/exception"
+	"govel/app/http/middleware"
+	"govel/app/model"
+	"govel/app/openapi"
+	"govel/app/service"
+	"govel/app/validation"
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LoginAttemptController struct{}
+
+func NewLoginAttemptController() LoginAttemptController {
+	return LoginAttemptController{}
+}
+
+func (controller *LoginAttemptController) Route(route fiber.Router) {
+	group := route.Group("/v1/login-attempts")
+	group.Post("/", middleware.Authenticate, controller.Index)
+
+	openapi.Describe(controller.Index, openapi.Doc{Summary: "List the failed login attempts", Validate: validation.LoginAttemptListValidate, Response: []model.GetLoginAttemptResponse{}, Paginated: true})
+}
+
+func (ctx *LoginAttemptController) Index(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	exception.PanicIfNeeded(err)
+
+	data, isNextPage := ctx.service(c).List(model.GetLoginAttemptRequest{
+		Token: c.FormValue("token"),
+		Email: c.Query("email"),
+		Page:  page,
+		Limit: 10,
+	})
+
+	// Return pagination response if next page is exist
+	if isNextPage {
+		return c.Status(200).JSON(model.PaginateResponse{
+			Code:    200,
+			Message: "OK",
+			Next:    fmt.Sprintf(c.BaseURL()+c.Path()+"?email=%s&page=%d", url.QueryEscape(c.Query("email")), page+1),
+			Data:    data,
+		})
+	}
+
+	// Return non pagination response
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data,
+	})
+}
+
+// Resolve the service from the container scope of the request
+func (ctx *LoginAttemptController) service(c *fiber.Ctx) service.LoginAttemptService {
+	return container.Make[service.LoginAttemptService](middleware.Scope(c))
+}
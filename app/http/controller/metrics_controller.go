@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+type MetricsController struct {
+	handler fasthttp.RequestHandler
+	path    string
+	// Bearer token of the scrape requests, not checked if empty
+	token string
+}
+
+func NewMetricsController(gatherer prometheus.Gatherer, path string, token string) MetricsController {
+	return MetricsController{
+		handler: fasthttpadaptor.NewFastHTTPHandler(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})),
+		path:    path,
+		token:   token,
+	}
+}
+
+func (controller *MetricsController) Route(route fiber.Router) {
+	route.Get(controller.path, controller.Show).Name("metrics")
+}
+
+// Respond the metrics in the Prometheus text format
+func (controller *MetricsController) Show(c *fiber.Ctx) error {
+	if controller.token != "" && subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), []byte("Bearer "+controller.token)) != 1 {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthenticated.")
+	}
+	controller.handler(c.Context())
+	return nil
+}
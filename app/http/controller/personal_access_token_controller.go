@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"govel/app/container"
+	"govel/app/exception"
+	"govel/app/http/middleware"
+	"govel/app/model"
+	"govel/app/openapi"
+	"govel/app/service"
+	"govel/app/validation"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PersonalAccessTokenController struct{}
+
+func NewPersonalAccessTokenController() PersonalAccessTokenController {
+	return PersonalAccessTokenController{}
+}
+
+func (controller *PersonalAccessTokenController) Route(route fiber.Router) {
+	group := route.Group("/v1/tokens", middleware.AuthenticateToken)
+	group.Get("/", middleware.Can("tokens:read"), controller.Index)
+	group.Post("/create", middleware.Can("tokens:write"), controller.Create)
+	group.Post("/delete/:id", middleware.Can("tokens:write"), controller.Delete)
+
+	openapi.Describe(controller.Index, openapi.Doc{Summary: "List the tokens of the user", Description: "Requires the tokens:read ability.", Response: []model.GetPersonalAccessTokenResponse{}})
+	openapi.Describe(controller.Create, openapi.Doc{Summary: "Create a personal access token", Description: "Requires the tokens:write ability. The abilities are comma separated like users:read,tokens:* and limited to the abilities of the authenticated token, its abilities if empty. The plain text token is responded once.", Validate: validation.PersonalAccessTokenCreateValidate, Response: model.CreatePersonalAccessTokenResponse{}})
+	openapi.Describe(controller.Delete, openapi.Doc{Summary: "Revoke the token", Description: "Requires the tokens:write ability.", Validate: validation.PersonalAccessTokenDeleteValidate, Response: model.DeletePersonalAccessTokenResponse{}})
+}
+
+func (ctx *PersonalAccessTokenController) Index(c *fiber.Ctx) error {
+	auth := c.Locals("auth").(model.AuthUser)
+
+	data := ctx.service(c).List(model.GetPersonalAccessTokenRequest{
+		UserId: auth.Id,
+	})
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data,
+	})
+}
+
+func (ctx *PersonalAccessTokenController) Create(c *fiber.Ctx) error {
+	auth := c.Locals("auth").(model.AuthUser)
+
+	expiresIn := 0
+	if c.FormValue("expires_in") != "" {
+		var err error
+		if expiresIn, err = strconv.Atoi(c.FormValue("expires_in")); err != nil {
+			panic(exception.ValidationError{
+				Message: "expires_in: must be an integer.",
+				Fields:  map[string]string{"expires_in": "must be an integer"},
+			})
+		}
+	}
+
+	// Abilities are comma separated, e.g. "users:read,tokens:*", the
+	// abilities of the authenticated token if empty
+	abilities := []string{}
+	for _, ability := range strings.Split(c.FormValue("abilities"), ",") {
+		if ability = strings.TrimSpace(ability); ability != "" {
+			abilities = append(abilities, ability)
+		}
+	}
+	if len(abilities) == 0 {
+		abilities = auth.Abilities
+	}
+
+	data := ctx.service(c).Create(model.CreatePersonalAccessTokenRequest{
+		UserId:           auth.Id,
+		Name:             c.FormValue("name"),
+		Abilities:        abilities,
+		ExpiresIn:        expiresIn,
+		GrantedAbilities: auth.Abilities,
+	})
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data,
+	})
+}
+
+func (ctx *PersonalAccessTokenController) Delete(c *fiber.Ctx) error {
+	auth := c.Locals("auth").(model.AuthUser)
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		panic(exception.ValidationError{
+			Message: "id: must be an integer.",
+			Fields:  map[string]string{"id": "must be an integer"},
+		})
+	}
+
+	data := ctx.service(c).Revoke(model.DeletePersonalAccessTokenRequest{
+		UserId: auth.Id,
+		Id:     id,
+	})
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data,
+	})
+}
+
+// Resolve the service from the container scope of the request
+func (ctx *PersonalAccessTokenController) service(c *fiber.Ctx) service.PersonalAccessTokenService {
+	return container.Make[service.PersonalAccessTokenService](middleware.Scope(c))
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"govel/app/container"
+	"govel/app/exception"
+	"govel/app/helper"
+	"govel/app/http/middleware"
+	"govel/app/model"
+	"govel/app/openapi"
+	"govel/app/service"
+	"govel/app/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt"
+)
+
+type TwoFactorController struct{}
+
+func NewTwoFactorController() TwoFactorController {
+	return TwoFactorController{}
+}
+
+func (controller *TwoFactorController) Route(route fiber.Router) {
+	group := route.Group("/v1/two-factor")
+	group.Post("/challenge", controller.Challenge).Name("two-factor-challenge")
+	group.Post("/enable", middleware.Authenticate, controller.Enable)
+	group.Post("/confirm", middleware.Authenticate, controller.Confirm)
+	group.Post("/disable", middleware.Authenticate, controller.Disable)
+	group.Post("/recovery-codes", middleware.Authenticate, controller.RecoveryCodes)
+	group.Post("/reset/:id", middleware.Authenticate, controller.Reset)
+
+	openapi.Describe(controller.Challenge, openapi.Doc{Summary: "Exchange the pending token with the code or a recovery code", Validate: validation.TwoFactorChallengeValidate, Response: model.TokenResponse{Claims: model.LoginUserResponse{}}})
+	openapi.Describe(controller.Enable, openapi.Doc{Summary: "Enable two factor authentication", Description: "Responds the secret to add to the authenticator app, confirm it with a code.", Validate: validation.TwoFactorEnableValidate, Response: model.EnableTwoFactorResponse{}})
+	openapi.Describe(controller.Confirm, openapi.Doc{Summary: "Confirm two factor authentication with a code", Validate: validation.TwoFactorConfirmValidate, Response: model.TwoFactorStatusResponse{}})
+	openapi.Describe(controller.Disable, openapi.Doc{Summary: "Disable two factor authentication", Validate: validation.TwoFactorConfirmValidate, Response: model.TwoFactorStatusResponse{}})
+	openapi.Describe(controller.RecoveryCodes, openapi.Doc{Summary: "Regenerate the recovery codes", Validate: validation.TwoFactorRecoveryCodesValidate, Response: model.RecoveryCodesTwoFactorResponse{}})
+	openapi.Describe(controller.Reset, openapi.Doc{Summary: "Reset two factor authentication of the user", Validate: validation.TwoFactorResetValidate, Response: model.TwoFactorStatusResponse{}})
+}
+
+func (ctx *TwoFactorController) Enable(c *fiber.Ctx) error {
+	data := ctx.service(c).Enable(model.EnableTwoFactorRequest{
+		Token: c.FormValue("token"),
+	})
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data,
+	})
+}
+
+func (ctx *TwoFactorController) Confirm(c *fiber.Ctx) error {
+	data := ctx.service(c).Confirm(model.ConfirmTwoFactorRequest{
+		Token:     c.FormValue("token"),
+		Code:      c.FormValue("code"),
+		Ip:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	})
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data,
+	})
+}
+
+func (ctx *TwoFactorController) Disable(c *fiber.Ctx) error {
+	data := ctx.service(c).Disable(model.ConfirmTwoFactorRequest{
+		Token:     c.FormValue("token"),
+		Code:      c.FormValue("code"),
+		Ip:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	})
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data,
+	})
+}
+
+func (ctx *TwoFactorController) RecoveryCodes(c *fiber.Ctx) error {
+	data := ctx.service(c).RegenerateRecoveryCodes(model.RecoveryCodesTwoFactorRequest{
+		Token: c.FormValue("token"),
+	})
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data,
+	})
+}
+
+func (ctx *TwoFactorController) Challenge(c *fiber.Ctx) error {
+	data := ctx.service(c).Challenge(model.ChallengeTwoFactorRequest{
+		Token:        c.FormValue("token"),
+		Code:         c.FormValue("code"),
+		RecoveryCode: c.FormValue("recovery_code"),
+		Ip:           c.IP(),
+		UserAgent:    c.Get(fiber.HeaderUserAgent),
+	})
+
+	token := helper.MakeECDSAToken(&data, jwt.SigningMethodES256)
+
+	refreshTokenURL, err := c.GetRouteURL("refresh-token", nil)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data: model.TokenResponse{
+			Type:            "bearer",
+			Alg:             "es256",
+			RefreshTokenURL: c.BaseURL() + refreshTokenURL,
+			Token:           token,
+			Claims:          data,
+		},
+	})
+}
+
+func (ctx *TwoFactorController) Reset(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	exception.PanicIfNeeded(err)
+
+	data := ctx.service(c).Reset(model.ResetTwoFactorRequest{
+		Token: c.FormValue("token"),
+		Id:    id,
+	})
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data,
+	})
+}
+
+// Resolve the service from the container scope of the request
+func (ctx *TwoFactorController) service(c *fiber.Ctx) service.TwoFactorService {
+	return container.Make[service.TwoFactorService](middleware.Scope(c))
+}
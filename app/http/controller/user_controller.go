@@ -2,23 +2,24 @@ package controller
 
 import (
 	"fmt"
+	"govel/app/container"
 	"govel/app/exception"
 	"govel/app/helper"
 	"govel/app/http/middleware"
 	"govel/app/model"
+	"govel/app/openapi"
 	"govel/app/service"
+	"govel/app/validation"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt"
 )
 
-type UserController struct {
-	service service.UserService
-}
+type UserController struct{}
 
-func NewUserController(service *service.UserService) UserController {
-	return UserController{service: *service}
+func NewUserController() UserController {
+	return UserController{}
 }
 
 func (controller *UserController) Route(route fiber.Router) {
@@ -34,13 +35,22 @@ func (controller *UserController) Route(route fiber.Router) {
 
 	// Add this endpoint at the bottom to avoid the path conflict
 	group.Get("/:id", controller.Show)
+
+	openapi.Describe(controller.Index, openapi.Doc{Summary: "List the users", Validate: validation.UserListhValidate, Response: []model.GetUserResponse{}, Paginated: true})
+	openapi.Describe(controller.Search, openapi.Doc{Summary: "Search the users by name", Validate: validation.UserSearchValidate, Response: []model.GetUserResponse{}, Paginated: true})
+	openapi.Describe(controller.Login, openapi.Doc{Summary: "Login with the email and password", Description: "Responds the pending token to exchange on the two factor challenge if enabled.", Validate: validation.UserLoginValidate, Response: model.TokenResponse{Claims: model.LoginUserResponse{}}})
+	openapi.Describe(controller.RefreshToken, openapi.Doc{Summary: "Refresh the token", Validate: validation.UserRefreshTokenValidate, Response: model.TokenResponse{Claims: model.RefreshTokenUserResponse{}}})
+	openapi.Describe(controller.Register, openapi.Doc{Summary: "Register the user", Validate: validation.UserRegisterValidate, Response: model.RegisterUserResponse{}})
+	openapi.Describe(controller.Update, openapi.Doc{Summary: "Update the profile of the user", Validate: validation.UserUpdateValidate, Response: model.UpdateUserResponse{}})
+	openapi.Describe(controller.Delete, openapi.Doc{Summary: "Delete the user", Validate: validation.UserDeleteValidate, Response: model.DeleteUserResponse{}})
+	openapi.Describe(controller.Show, openapi.Doc{Summary: "Get the user", Fields: []string{"id"}, Validate: validation.UserShowValidate, Response: model.GetUserResponse{}})
 }
 
 func (ctx *UserController) Index(c *fiber.Ctx) error {
 	page, err := strconv.Atoi(c.Query("page", "1"))
 	exception.PanicIfNeeded(err)
 
-	data, isNextPage := ctx.service.List(model.GetUserRequest{
+	data, isNextPage := ctx.service(c).List(model.GetUserRequest{
 		Page:  page,
 		Limit: 10,
 	})
@@ -67,7 +77,7 @@ func (ctx *UserController) Search(c *fiber.Ctx) error {
 	page, err := strconv.Atoi(c.Query("page", "1"))
 	exception.PanicIfNeeded(err)
 
-	data, isNextPage := ctx.service.SearchList(model.GetUserRequest{
+	data, isNextPage := ctx.service(c).SearchList(model.GetUserRequest{
 		Query: c.Params("query"),
 		Page:  page,
 		Limit: 10,
@@ -92,13 +102,33 @@ func (ctx *UserController) Search(c *fiber.Ctx) error {
 }
 
 func (ctx *UserController) Login(c *fiber.Ctx) error {
-	data := ctx.service.Login(model.LoginUserRequest{
-		Email:    c.FormValue("email"),
-		Password: c.FormValue("password"),
+	data := ctx.service(c).Login(model.LoginUserRequest{
+		Email:     c.FormValue("email"),
+		Password:  c.FormValue("password"),
+		Ip:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
 	})
 
 	token := helper.MakeECDSAToken(&data, jwt.SigningMethodES256)
 
+	// Return pending token to be exchanged on the two factor challenge
+	if data.Scope == model.TwoFactorPendingScope {
+		challengeURL, err := c.GetRouteURL("two-factor-challenge", nil)
+		exception.PanicIfNeeded(err)
+
+		return c.Status(200).JSON(model.WebResponse{
+			Code:    200,
+			Message: "OK",
+			Data: model.TokenResponse{
+				Type:         model.TwoFactorPendingScope,
+				Alg:          "es256",
+				ChallengeURL: c.BaseURL() + challengeURL,
+				Token:        token,
+				Claims:       data,
+			},
+		})
+	}
+
 	refreshTokenURL, err := c.GetRouteURL("refresh-token", nil)
 	exception.PanicIfNeeded(err)
 
@@ -116,7 +146,7 @@ func (ctx *UserController) Login(c *fiber.Ctx) error {
 }
 
 func (ctx *UserController) RefreshToken(c *fiber.Ctx) error {
-	data := ctx.service.RefreshToken(model.RefreshTokenUserRequest{
+	data := ctx.service(c).RefreshToken(model.RefreshTokenUserRequest{
 		Token: c.FormValue("token"),
 	})
 
@@ -139,7 +169,7 @@ func (ctx *UserController) RefreshToken(c *fiber.Ctx) error {
 }
 
 func (ctx *UserController) Register(c *fiber.Ctx) error {
-	data := ctx.service.Register(model.RegisterUserRequest{
+	data := ctx.service(c).Register(model.RegisterUserRequest{
 		SocialId:   c.FormValue("social_id"),
 		Email:      c.FormValue("email"),
 		Name:       c.FormValue("name"),
@@ -157,7 +187,7 @@ func (ctx *UserController) Show(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	exception.PanicIfNeeded(err)
 
-	data := ctx.service.Single(model.GetUserRequest{
+	data := ctx.service(c).Single(model.GetUserRequest{
 		Id: id,
 	})
 
@@ -173,7 +203,7 @@ func (ctx *UserController) Update(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	exception.PanicIfNeeded(err)
 
-	data := ctx.service.Update(model.UpdateUserRequest{
+	data := ctx.service(c).Update(model.UpdateUserRequest{
 		Token:    c.FormValue("token"),
 		Id:       id,
 		Name:     c.FormValue("name"),
@@ -193,7 +223,7 @@ func (ctx *UserController) Delete(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	exception.PanicIfNeeded(err)
 
-	data := ctx.service.Delete(model.DeleteUserRequest{
+	data := ctx.service(c).Delete(model.DeleteUserRequest{
 		Token: c.FormValue("token"),
 		Id:    id,
 	})
@@ -205,3 +235,8 @@ func (ctx *UserController) Delete(c *fiber.Ctx) error {
 		Data:    data,
 	})
 }
+
+// Resolve the service from the container scope of the request
+func (ctx *UserController) service(c *fiber.Ctx) service.UserService {
+	return container.Make[service.UserService](middleware.Scope(c))
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"govel/app/container"
+	"govel/app/http/middleware"
+	"govel/app/model"
+	"govel/app/service"
+	"govel/app/session"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// Lifetime of the remember me cookie
+	rememberLifetime = 365 * 24 * time.Hour
+
+	// Session keys of the login waiting for the two factor challenge
+	twoFactorTokenKey    = "_two_factor_token"
+	twoFactorRememberKey = "_two_factor_remember"
+)
+
+type WebAuthController struct{}
+
+func NewWebAuthController() WebAuthController {
+	return WebAuthController{}
+}
+
+func (controller *WebAuthController) Route(route fiber.Router) {
+	route.Get("/csrf-token", controller.CsrfToken)
+	route.Post("/login", controller.Login)
+	route.Post("/two-factor-challenge", controller.Challenge)
+	route.Post("/logout", middleware.AuthenticateSession, controller.Logout)
+	route.Get("/account", middleware.AuthenticateSession, controller.Account)
+}
+
+func (ctx *WebAuthController) CsrfToken(c *fiber.Ctx) error {
+	current := c.Locals("session").(*session.Session)
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    fiber.Map{"token": current.Token()},
+	})
+}
+
+func (ctx *WebAuthController) Login(c *fiber.Ctx) error {
+	current := c.Locals("session").(*session.Session)
+
+	remember := c.FormValue("remember") == "1" || c.FormValue("remember") == "true" || c.FormValue("remember") == "on"
+	data := ctx.service(c).Login(model.WebLoginRequest{
+		Email:     c.FormValue("email"),
+		Password:  c.FormValue("password"),
+		Remember:  remember,
+		Ip:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	})
+
+	// Keep the pending token until the two factor challenge passed
+	if data.TwoFactorToken != "" {
+		current.Put(twoFactorTokenKey, data.TwoFactorToken)
+		current.Put(twoFactorRememberKey, strconv.FormatBool(remember))
+
+		return c.Status(200).JSON(model.WebResponse{
+			Code:    200,
+			Message: "OK",
+			Data:    fiber.Map{"two_factor": true},
+		})
+	}
+
+	return ctx.loggedIn(c, data)
+}
+
+func (ctx *WebAuthController) Challenge(c *fiber.Ctx) error {
+	current := c.Locals("session").(*session.Session)
+
+	data := ctx.service(c).Challenge(model.WebChallengeRequest{
+		Token:        current.Get(twoFactorTokenKey),
+		Code:         c.FormValue("code"),
+		RecoveryCode: c.FormValue("recovery_code"),
+		Remember:     current.Get(twoFactorRememberKey) == "true",
+		Ip:           c.IP(),
+		UserAgent:    c.Get(fiber.HeaderUserAgent),
+	})
+	current.Forget(twoFactorTokenKey)
+	current.Forget(twoFactorRememberKey)
+
+	return ctx.loggedIn(c, data)
+}
+
+func (ctx *WebAuthController) Logout(c *fiber.Ctx) error {
+	current := c.Locals("session").(*session.Session)
+	auth := c.Locals("auth").(model.AuthUser)
+
+	ctx.service(c).Logout(auth.Id)
+	current.Invalidate()
+	ctx.manager(c).SetCookie(c, middleware.RememberCookie, "", -time.Hour)
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    "Logged out.",
+	})
+}
+
+func (ctx *WebAuthController) Account(c *fiber.Ctx) error {
+	auth := c.Locals("auth").(model.AuthUser)
+
+	data := ctx.userService(c).Single(model.GetUserRequest{
+		Id: int(auth.Id),
+	})
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data,
+	})
+}
+
+// Save the user to the session and send the remember me cookie
+func (ctx *WebAuthController) loggedIn(c *fiber.Ctx, data model.WebLoginResponse) error {
+	current := c.Locals("session").(*session.Session)
+
+	// Generate new session id and CSRF token to avoid session fixation
+	current.Regenerate()
+	current.RegenerateToken()
+	current.Put(session.AuthKey, strconv.FormatUint(uint64(data.User.Id), 10))
+
+	if data.RememberToken != "" {
+		ctx.manager(c).SetCookie(c, middleware.RememberCookie, data.RememberToken, rememberLifetime)
+	}
+
+	return c.Status(200).JSON(model.WebResponse{
+		Code:    200,
+		Message: "OK",
+		Data:    data.User,
+	})
+}
+
+// Resolve the services from the container scope of the request
+func (ctx *WebAuthController) service(c *fiber.Ctx) service.WebAuthService {
+	return container.Make[service.WebAuthService](middleware.Scope(c))
+}
+
+func (ctx *WebAuthController) userService(c *fiber.Ctx) service.UserService {
+	return container.Make[service.UserService](middleware.Scope(c))
+}
+
+func (ctx *WebAuthController) manager(c *fiber.Ctx) *session.Manager {
+	return container.Make[*session.Manager](middleware.Scope(c))
+}
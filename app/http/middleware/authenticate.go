@@ -3,14 +3,25 @@ package middleware
 import (
 	"govel/app/exception"
 	"govel/app/helper"
+	"govel/app/model"
+	"govel/app/openapi"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
 )
 
+func init() {
+	openapi.Secure(Authenticate, "token", openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "query",
+		Name:        "token",
+		Description: "JWT of the login, read from the token form field too",
+	})
+}
+
 func Authenticate(c *fiber.Ctx) error {
 	token := helper.ParseECDSAToken(c.FormValue("token", ""), jwt.SigningMethodES256)
-	if !token.Valid {
+	if !token.Valid || token.Claims.(jwt.MapClaims)["scope"] == model.TwoFactorPendingScope {
 		exception.PanicResponse("Token invalid.")
 	}
 	return c.Next()
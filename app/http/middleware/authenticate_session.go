@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"govel/app/container"
+	"govel/app/service"
+	"govel/app/session"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Name of the remember me cookie
+const RememberCookie = "remember_web"
+
+// Guard for the web route using the session, fallback to the remember me
+// cookie. Must be used after StartSession.
+func AuthenticateSession(c *fiber.Ctx) error {
+	webAuthService := container.Make[service.WebAuthService](Scope(c))
+	current := c.Locals("session").(*session.Session)
+
+	// Check the user from the session
+	if id, err := strconv.ParseUint(current.Get(session.AuthKey), 10, 64); err == nil {
+		if auth := webAuthService.Authenticate(uint(id)); auth != nil {
+			c.Locals("auth", *auth)
+			return c.Next()
+		}
+		current.Forget(session.AuthKey)
+	}
+
+	// Login again from the remember me cookie
+	if remember := c.Cookies(RememberCookie); remember != "" {
+		if auth := webAuthService.Recall(remember); auth != nil {
+			current.Regenerate()
+			current.Put(session.AuthKey, strconv.FormatUint(uint64(auth.Id), 10))
+			c.Locals("auth", *auth)
+			return c.Next()
+		}
+	}
+
+	return fiber.NewError(fiber.StatusUnauthorized, "Unauthenticated.")
+}
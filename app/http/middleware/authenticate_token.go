@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"govel/app/container"
+	"govel/app/exception"
+	"govel/app/helper"
+	"govel/app/model"
+	"govel/app/openapi"
+	"govel/app/service"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func init() {
+	openapi.Secure(AuthenticateToken, "bearer", openapi.SecurityScheme{
+		Type:        "http",
+		Scheme:      "bearer",
+		Description: "JWT of the login or personal access token like {id}|{secret}",
+	})
+}
+
+// Guard accepting both JWT and personal access tokens. The token is read from
+// the "Authorization: Bearer" header or the "token" form value, the
+// authenticated user is saved to the fiber context as "auth".
+func AuthenticateToken(c *fiber.Ctx) error {
+	token := bearerToken(c)
+	if token == "" {
+		exception.PanicResponse("Token invalid.")
+	}
+
+	// Personal access token has format "{id}|{secret}"
+	if strings.Contains(token, "|") {
+		tokenService := container.Make[service.PersonalAccessTokenService](Scope(c))
+		c.Locals("auth", tokenService.Authenticate(token))
+		return c.Next()
+	}
+
+	// Otherwise parse it as JWT, which is allowed to do everything
+	jwtToken := helper.ParseECDSAToken(token, jwt.SigningMethodES256)
+	claims := jwtToken.Claims.(jwt.MapClaims)
+	if !jwtToken.Valid || claims["scope"] == model.TwoFactorPendingScope {
+		exception.PanicResponse("Token invalid.")
+	}
+	id, _ := claims["id"].(float64)
+	role, _ := claims["role"].(float64)
+	c.Locals("auth", model.AuthUser{
+		Id:        uint(id),
+		Role:      int(role),
+		Guard:     "jwt",
+		Abilities: []string{"*"},
+	})
+	return c.Next()
+}
+
+// Check the authenticated token has all of the abilities
+func Can(abilities ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth, ok := c.Locals("auth").(model.AuthUser)
+		if !ok {
+			exception.PanicResponse("Unauthorized")
+		}
+		for _, ability := range abilities {
+			if !helper.HasAbility(auth.Abilities, ability) {
+				panic(exception.ForbiddenError{Message: "Token doesn't have the " + ability + " ability."})
+			}
+		}
+		return c.Next()
+	}
+}
+
+func bearerToken(c *fiber.Ctx) string {
+	header := c.Get(fiber.HeaderAuthorization)
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return c.FormValue("token", "")
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"govel/app/connection"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const DebugQueriesHeader = "X-Debug-Queries"
+
+// Log the queries of the request, respond their count in X-Debug-Queries
+// and warn of the query run at least repeatedThreshold times, likely an N+1
+// query. Use it only when debugging, the queries are kept in memory. The
+// query log is saved to the fiber context as "queries" for the error handler.
+func InspectQueries(repeatedThreshold int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(connection.WithQueryLog(c.UserContext()))
+		c.Locals("queries", connection.QueryLogFrom(c.UserContext()))
+		// Deferred to inspect the requests failed with a panic too
+		defer inspectQueries(c, repeatedThreshold)
+		return c.Next()
+	}
+}
+
+func inspectQueries(c *fiber.Ctx, repeatedThreshold int) {
+	queries := connection.QueryLogFrom(c.UserContext())
+	c.Set(DebugQueriesHeader, strconv.Itoa(len(queries.Queries())))
+	if repeatedThreshold == 0 {
+		return
+	}
+	for _, repeated := range queries.Repeated(repeatedThreshold) {
+		slog.WarnContext(c.UserContext(), "repeated query, likely N+1",
+			slog.String("path", c.Path()),
+			slog.String("sql", repeated.Statement),
+			slog.Int("count", repeated.Count),
+			slog.String("caller", repeated.Caller),
+		)
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"govel/app/model"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Log every request with the status and the latency. The error is rendered
+// here to log the final status, so use it before the recover middleware.
+func LogRequest(c *fiber.Ctx) error {
+	start := time.Now()
+	if err := c.Next(); err != nil {
+		if err := c.App().ErrorHandler(c, err); err != nil {
+			c.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
+	status := c.Response().StatusCode()
+	level := slog.LevelInfo
+	if status >= 500 {
+		level = slog.LevelError
+	} else if status >= 400 {
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", c.Method()),
+		slog.String("path", c.Path()),
+		slog.Int("status", status),
+		slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		slog.String("ip", c.IP()),
+	}
+	if auth, ok := c.Locals("auth").(model.AuthUser); ok {
+		attrs = append(attrs, slog.Uint64("user_id", uint64(auth.Id)))
+	}
+	// The request ID is added by the logger from the context
+	slog.LogAttrs(c.UserContext(), level, "request", attrs...)
+	return nil
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Count the requests and observe their durations by the route template like
+// "/api/v1/users/show/:id", never by the raw path. Use it before LogRequest
+// so the status is final.
+func Metrics(registerer prometheus.Registerer) fiber.Handler {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of the HTTP requests.",
+	}, []string{"method", "route", "status"})
+	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of the HTTP requests in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+	inFlight := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "Number of the HTTP requests being served.",
+	})
+	registerer.MustRegister(requests, durations, inFlight)
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		err := c.Next()
+		if err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		route, ok := c.Locals("route").(string)
+		if !ok {
+			route = c.Route().Path
+		}
+		// Cloned, the strings of fiber are reused after the request
+		method, route := strings.Clone(c.Method()), strings.Clone(route)
+		requests.WithLabelValues(method, route, strconv.Itoa(c.Response().StatusCode())).Inc()
+		durations.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
@@ -0,0 +1,10 @@
+package middleware
+
+import "github.com/gofiber/fiber/v2"
+
+// Respond 404 if no route matched, use it last. The metrics count these
+// requests as the "unmatched" route.
+func NotFound(c *fiber.Ctx) error {
+	c.Locals("route", "unmatched")
+	return fiber.ErrNotFound
+}
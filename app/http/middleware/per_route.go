@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Router adding the middleware in front of the handlers of every route
+// registered through it. Unlike Use the middleware don't run for the paths
+// without a route like the 404 responses.
+type perRouteRouter struct {
+	fiber.Router
+	middleware []fiber.Handler
+}
+
+// Add the middleware to the routes registered through the returned router
+func PerRoute(router fiber.Router, middleware ...fiber.Handler) fiber.Router {
+	return &perRouteRouter{Router: router, middleware: middleware}
+}
+
+func (router *perRouteRouter) handlers(handlers []fiber.Handler) []fiber.Handler {
+	return append(append([]fiber.Handler{}, router.middleware...), handlers...)
+}
+
+// Fiber adds the HEAD route of the GET route with the same handlers
+func (router *perRouteRouter) Get(path string, handlers ...fiber.Handler) fiber.Router {
+	router.Router.Get(path, router.handlers(handlers)...)
+	return router
+}
+
+func (router *perRouteRouter) Head(path string, handlers ...fiber.Handler) fiber.Router {
+	return router.Add(fiber.MethodHead, path, handlers...)
+}
+
+func (router *perRouteRouter) Post(path string, handlers ...fiber.Handler) fiber.Router {
+	return router.Add(fiber.MethodPost, path, handlers...)
+}
+
+func (router *perRouteRouter) Put(path string, handlers ...fiber.Handler) fiber.Router {
+	return router.Add(fiber.MethodPut, path, handlers...)
+}
+
+func (router *perRouteRouter) Delete(path string, handlers ...fiber.Handler) fiber.Router {
+	return router.Add(fiber.MethodDelete, path, handlers...)
+}
+
+func (router *perRouteRouter) Connect(path string, handlers ...fiber.Handler) fiber.Router {
+	return router.Add(fiber.MethodConnect, path, handlers...)
+}
+
+func (router *perRouteRouter) Options(path string, handlers ...fiber.Handler) fiber.Router {
+	return router.Add(fiber.MethodOptions, path, handlers...)
+}
+
+func (router *perRouteRouter) Trace(path string, handlers ...fiber.Handler) fiber.Router {
+	return router.Add(fiber.MethodTrace, path, handlers...)
+}
+
+func (router *perRouteRouter) Patch(path string, handlers ...fiber.Handler) fiber.Router {
+	return router.Add(fiber.MethodPatch, path, handlers...)
+}
+
+func (router *perRouteRouter) Add(method, path string, handlers ...fiber.Handler) fiber.Router {
+	router.Router.Add(method, path, router.handlers(handlers)...)
+	return router
+}
+
+func (router *perRouteRouter) All(path string, handlers ...fiber.Handler) fiber.Router {
+	router.Router.All(path, router.handlers(handlers)...)
+	return router
+}
+
+func (router *perRouteRouter) Group(prefix string, handlers ...fiber.Handler) fiber.Router {
+	return PerRoute(router.Router.Group(prefix, handlers...), router.middleware...)
+}
+
+func (router *perRouteRouter) Route(prefix string, fn func(router fiber.Router), name ...string) fiber.Router {
+	group := router.Group(prefix)
+	if len(name) > 0 {
+		group.Name(name[0])
+	}
+	fn(group)
+	return group
+}
+
+func (router *perRouteRouter) Name(name string) fiber.Router {
+	router.Router.Name(name)
+	return router
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"govel/app/requestid"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Read the X-Request-ID of the request or generate one, save it to the user
+// context and respond it. Use it first so every log line has the ID.
+func RequestID(c *fiber.Ctx) error {
+	id := requestid.Normalize(c.Get(requestid.Header))
+	c.SetUserContext(requestid.WithContext(c.UserContext(), id))
+	c.Locals("request_id", id)
+	c.Set(requestid.Header, id)
+	return c.Next()
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"govel/app/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Start the container scope of the request and save it to the fiber context
+// as "container", the scoped bindings are resolved once per request with the
+// request context
+func StartScope(root *container.Container) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("container", root.Scope(c.UserContext()))
+		return c.Next()
+	}
+}
+
+// Get the container scope of the request, must be used after StartScope
+func Scope(c *fiber.Ctx) *container.Container {
+	return c.Locals("container").(*container.Container)
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"govel/app/container"
+	"govel/app/session"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Load the session and save it to the fiber context as "session", the
+// session is written back to the store after the request handled
+func StartSession(c *fiber.Ctx) error {
+	manager := container.Make[*session.Manager](Scope(c))
+	current := manager.Start(c)
+	c.Locals("session", current)
+
+	err := c.Next()
+	manager.Save(c, current)
+	return err
+}
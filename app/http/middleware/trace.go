@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gofiber/contrib/otelfiber"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Start the server span of the request, continuing the trace of the
+// traceparent header. The span is named by the route template after the
+// request, use it before LogRequest so the status is final.
+func Trace(provider trace.TracerProvider) fiber.Handler {
+	return otelfiber.Middleware(
+		otelfiber.WithTracerProvider(provider),
+		otelfiber.WithPropagators(propagation.TraceContext{}),
+		otelfiber.WithSpanNameFormatter(func(c *fiber.Ctx) string {
+			route, ok := c.Locals("route").(string)
+			if !ok {
+				route = c.Route().Path
+			}
+			// The strings of fiber are reused after the request, copy them
+			return utils.CopyString(c.Method()) + " " + route
+		}),
+	)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"govel/app/session"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Compare the "_token" form value or "X-CSRF-TOKEN" header with the session
+// token on state changing requests. Must be used after StartSession.
+func VerifyCsrfToken(c *fiber.Ctx) error {
+	current := c.Locals("session").(*session.Session)
+
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return c.Next()
+	}
+
+	token := c.FormValue(session.TokenKey)
+	if token == "" {
+		token = c.Get("X-CSRF-TOKEN")
+	}
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(current.Token())) != 1 {
+		return fiber.NewError(fiber.StatusForbidden, "CSRF token mismatch.")
+	}
+	return c.Next()
+}
@@ -0,0 +1,41 @@
+package httpclient
+
+import (
+	"govel/app/requestid"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Make the client of the outgoing calls, the request ID of the request
+// context is forwarded as X-Request-ID and the call is traced with the
+// provider and forwarded as traceparent
+func New(timeout time.Duration, provider trace.TracerProvider) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &Transport{Base: otelhttp.NewTransport(http.DefaultTransport,
+			otelhttp.WithTracerProvider(provider),
+			otelhttp.WithPropagators(propagation.TraceContext{}),
+		)},
+	}
+}
+
+// Round tripper forwarding the request ID, use it to wrap your own transport
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (transport *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	id := requestid.FromContext(request.Context())
+	if id == "" || request.Header.Get(requestid.Header) != "" {
+		return transport.Base.RoundTrip(request)
+	}
+
+	// The request must not be changed, send the clone with the header
+	clone := request.Clone(request.Context())
+	clone.Header.Set(requestid.Header, id)
+	return transport.Base.RoundTrip(clone)
+}
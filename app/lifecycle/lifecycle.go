@@ -0,0 +1,84 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Hooks run when the app boots and shuts down
+type Lifecycle struct {
+	boot         []hook
+	shutdown     []hook
+	shuttingDown bool
+	mutex        sync.Mutex
+}
+
+func New() *Lifecycle {
+	return &Lifecycle{}
+}
+
+// Run the hook when the app boots, the hooks run in order
+func (lifecycle *Lifecycle) OnBoot(name string, fn func(ctx context.Context) error) {
+	lifecycle.mutex.Lock()
+	defer lifecycle.mutex.Unlock()
+	lifecycle.boot = append(lifecycle.boot, hook{name: name, fn: fn})
+}
+
+// Run the hook when the app shuts down, the hooks run in reverse order so
+// the later booted is closed first
+func (lifecycle *Lifecycle) OnShutdown(name string, fn func(ctx context.Context) error) {
+	lifecycle.mutex.Lock()
+	defer lifecycle.mutex.Unlock()
+	lifecycle.shutdown = append(lifecycle.shutdown, hook{name: name, fn: fn})
+}
+
+// Run the boot hooks, stop on the first error
+func (lifecycle *Lifecycle) Boot(ctx context.Context) error {
+	lifecycle.mutex.Lock()
+	hooks := append([]hook{}, lifecycle.boot...)
+	lifecycle.mutex.Unlock()
+
+	for _, hook := range hooks {
+		if err := hook.fn(ctx); err != nil {
+			return errors.New(hook.name + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// Run every shutdown hook once even if some failed, the errors are joined
+func (lifecycle *Lifecycle) Shutdown(ctx context.Context) error {
+	lifecycle.mutex.Lock()
+	if lifecycle.shuttingDown {
+		lifecycle.mutex.Unlock()
+		return nil
+	}
+	lifecycle.shuttingDown = true
+	hooks := append([]hook{}, lifecycle.shutdown...)
+	lifecycle.mutex.Unlock()
+
+	messages := []string{}
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].fn(ctx); err != nil {
+			messages = append(messages, hooks[i].name+": "+err.Error())
+		}
+	}
+	if len(messages) > 0 {
+		return errors.New("Shutdown failed:\n- " + strings.Join(messages, "\n- "))
+	}
+	return nil
+}
+
+// Check the shutdown started, the app should stop taking new work
+func (lifecycle *Lifecycle) ShuttingDown() bool {
+	lifecycle.mutex.Lock()
+	defer lifecycle.mutex.Unlock()
+	return lifecycle.shuttingDown
+}
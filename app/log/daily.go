@@ -0,0 +1,104 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const dateFormat = "2006-01-02"
+
+// Writer to the file of the day like storage/logs/govel-2023-01-31.log, the
+// files older than the days are deleted on rotation
+type DailyWriter struct {
+	path   string
+	prefix string
+	days   int
+	date   string
+	file   *os.File
+	// Clock of the file date, swap it on tests
+	Now   func() time.Time
+	mutex sync.Mutex
+}
+
+func NewDailyWriter(path string, prefix string, days int) (*DailyWriter, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	return &DailyWriter{path: path, prefix: prefix, days: days, Now: time.Now}, nil
+}
+
+func (writer *DailyWriter) Write(p []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	if err := writer.rotate(); err != nil {
+		return 0, err
+	}
+	return writer.file.Write(p)
+}
+
+func (writer *DailyWriter) Close() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	if writer.file == nil {
+		return nil
+	}
+	err := writer.file.Close()
+	writer.file = nil
+	writer.date = ""
+	return err
+}
+
+// Path of the current file
+func (writer *DailyWriter) Filename() string {
+	return writer.filename(writer.Now().Format(dateFormat))
+}
+
+func (writer *DailyWriter) filename(date string) string {
+	return filepath.Join(writer.path, writer.prefix+"-"+date+".log")
+}
+
+// Open the file of today if the day changed
+func (writer *DailyWriter) rotate() error {
+	date := writer.Now().Format(dateFormat)
+	if writer.file != nil && writer.date == date {
+		return nil
+	}
+
+	file, err := os.OpenFile(writer.filename(date), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if writer.file != nil {
+		writer.file.Close()
+	}
+	writer.file = file
+	writer.date = date
+	writer.prune()
+	return nil
+}
+
+// Delete the files older than the days, the error is ignored to keep logging
+func (writer *DailyWriter) prune() {
+	if writer.days <= 0 {
+		return
+	}
+	files, err := filepath.Glob(filepath.Join(writer.path, writer.prefix+"-*.log"))
+	if err != nil {
+		return
+	}
+	sort.Strings(files)
+
+	oldest := writer.Now().AddDate(0, 0, -writer.days+1).Format(dateFormat)
+	for _, file := range files {
+		date := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), writer.prefix+"-"), ".log")
+		if _, err := time.Parse(dateFormat, date); err == nil && date < oldest {
+			os.Remove(file)
+		}
+	}
+}
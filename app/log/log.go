@@ -0,0 +1,152 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"govel/app/requestid"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Config struct {
+	// stdout, json, daily or stack
+	Channel string
+	// Channels of the stack channel
+	Stack []string
+	Level slog.Level
+	// Directory of the daily files
+	Path string
+	// Days to keep the daily files, zero keeps them forever
+	Days int
+}
+
+// Make the logger of the channel, the returned closer closes the files
+func New(config Config) (*slog.Logger, io.Closer, error) {
+	handler, closer, err := newHandler(config.Channel, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return slog.New(contextHandler{handler}), closer, nil
+}
+
+func newHandler(channel string, config Config) (slog.Handler, io.Closer, error) {
+	options := &slog.HandlerOptions{Level: config.Level}
+	switch channel {
+	case "stdout":
+		return slog.NewTextHandler(os.Stdout, options), nopCloser{}, nil
+	case "json":
+		return slog.NewJSONHandler(os.Stdout, options), nopCloser{}, nil
+	case "daily":
+		writer, err := NewDailyWriter(config.Path, "govel", config.Days)
+		if err != nil {
+			return nil, nil, err
+		}
+		return slog.NewJSONHandler(writer, options), writer, nil
+	case "stack":
+		handlers := []slog.Handler{}
+		closers := closers{}
+		for _, name := range config.Stack {
+			if name == "stack" {
+				return nil, nil, errors.New("stack channel can't contain itself")
+			}
+			handler, closer, err := newHandler(name, config)
+			if err != nil {
+				closers.Close()
+				return nil, nil, err
+			}
+			handlers = append(handlers, handler)
+			closers = append(closers, closer)
+		}
+		return fanout(handlers), closers, nil
+	}
+	return nil, nil, fmt.Errorf("log channel %q is not supported", channel)
+}
+
+// Parse the level like "debug", "info", "warn" or "error"
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(strings.ToLower(value)))
+	return level, err
+}
+
+// Add the request ID and the trace ID of the context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (handler contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+	}
+	return handler.Handler.Handle(ctx, record)
+}
+
+func (handler contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{handler.Handler.WithAttrs(attrs)}
+}
+
+func (handler contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{handler.Handler.WithGroup(name)}
+}
+
+// Send the records to every handler
+type fanout []slog.Handler
+
+func (handlers fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (handlers fanout) Handle(ctx context.Context, record slog.Record) error {
+	errs := []error{}
+	for _, handler := range handlers {
+		if handler.Enabled(ctx, record.Level) {
+			errs = append(errs, handler.Handle(ctx, record.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (handlers fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	result := fanout{}
+	for _, handler := range handlers {
+		result = append(result, handler.WithAttrs(attrs))
+	}
+	return result
+}
+
+func (handlers fanout) WithGroup(name string) slog.Handler {
+	result := fanout{}
+	for _, handler := range handlers {
+		result = append(result, handler.WithGroup(name))
+	}
+	return result
+}
+
+type nopCloser struct{}
+
+func (closer nopCloser) Close() error {
+	return nil
+}
+
+type closers []io.Closer
+
+func (items closers) Close() error {
+	errs := []error{}
+	for _, closer := range items {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
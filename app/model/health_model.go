@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type DatabaseHealthResponse struct {
+	Connection      string    `json:"connection"`
+	Healthy         bool      `json:"healthy"`
+	Error           string    `json:"error,omitempty"`
+	LatencyMs       float64   `json:"latency_ms"`
+	CheckedAt       time.Time `json:"checked_at"`
+	Failures        int64     `json:"failures"`
+	OpenConnections int       `json:"open_connections"`
+	InUse           int       `json:"in_use"`
+	Idle            int       `json:"idle"`
+	WaitCount       int64     `json:"wait_count"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type GetLoginAttemptRequest struct {
+	Token string `json:"token" form:"token"`
+	Email string `json:"email" query:"email"`
+	Page  int    `json:"page" query:"page" default:"1"`
+	Limit int    `json:"limit"`
+}
+
+type GetLoginAttemptResponse struct {
+	Id        uint      `json:"id"`
+	Email     string    `json:"email"`
+	Ip        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+type AuthUser struct {
+	Id        uint     `json:"id"`
+	Role      int      `json:"role"`
+	Guard     string   `json:"guard"`
+	TokenId   uint     `json:"token_id,omitempty"`
+	Abilities []string `json:"abilities"`
+}
+
+type CreatePersonalAccessTokenRequest struct {
+	UserId    uint     `json:"user_id"`
+	Name      string   `json:"name" form:"name"`
+	Abilities []string `json:"abilities" form:"abilities"`
+	ExpiresIn int      `json:"expires_in" form:"expires_in"`
+	// Abilities of the authenticated token, the new token can't have more
+	GrantedAbilities []string `json:"-"`
+}
+
+type CreatePersonalAccessTokenResponse struct {
+	Id             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Abilities      []string   `json:"abilities"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	PlainTextToken string     `json:"plain_text_token"`
+}
+
+type GetPersonalAccessTokenRequest struct {
+	UserId uint `json:"user_id"`
+}
+
+type GetPersonalAccessTokenResponse struct {
+	Id         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Abilities  []string   `json:"abilities"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type DeletePersonalAccessTokenRequest struct {
+	UserId uint `json:"user_id"`
+	Id     int  `json:"id" params:"id"`
+}
+
+type DeletePersonalAccessTokenResponse struct {
+	Id      uint   `json:"id"`
+	Message string `json:"message"`
+}
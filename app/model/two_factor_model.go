@@ -0,0 +1,48 @@
+package model
+
+// Scope of the token issued by login when the two factor challenge is required
+const TwoFactorPendingScope = "2fa-pending"
+
+type EnableTwoFactorRequest struct {
+	Token string `json:"token" form:"token"`
+}
+
+type EnableTwoFactorResponse struct {
+	Secret        string   `json:"secret"`
+	URI           string   `json:"uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type ConfirmTwoFactorRequest struct {
+	Token     string `json:"token" form:"token"`
+	Code      string `json:"code" form:"code"`
+	Ip        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+type TwoFactorStatusResponse struct {
+	Id      uint   `json:"id"`
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+type RecoveryCodesTwoFactorRequest struct {
+	Token string `json:"token" form:"token"`
+}
+
+type RecoveryCodesTwoFactorResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type ChallengeTwoFactorRequest struct {
+	Token        string `json:"token" form:"token"`
+	Code         string `json:"code" form:"code"`
+	RecoveryCode string `json:"recovery_code" form:"recovery_code"`
+	Ip           string `json:"ip"`
+	UserAgent    string `json:"user_agent"`
+}
+
+type ResetTwoFactorRequest struct {
+	Token string `json:"token" form:"token"`
+	Id    int    `json:"id" params:"id"`
+}
@@ -3,7 +3,7 @@ package model
 import "github.com/golang-jwt/jwt/v4"
 
 type RefreshTokenUserRequest struct {
-	Token string `json:"token"`
+	Token string `json:"token" form:"token"`
 }
 
 type RefreshTokenUserResponse struct {
@@ -20,8 +20,10 @@ type RefreshTokenUserResponse struct {
 }
 
 type LoginUserRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email     string `json:"email" form:"email"`
+	Password  string `json:"password" form:"password"`
+	Ip        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
 }
 
 type LoginUserResponse struct {
@@ -34,15 +36,16 @@ type LoginUserResponse struct {
 	Location string `json:"location"`
 	Desc     string `json:"desc"`
 	Role     int    `json:"role"`
+	Scope    string `json:"scope,omitempty"`
 	jwt.StandardClaims
 }
 
 type RegisterUserRequest struct {
-	SocialId   string `json:"social_id"`
-	Email      string `json:"email"`
-	Name       string `json:"name"`
-	Password   string `json:"password"`
-	Repassword string `json:"repassword"`
+	SocialId   string `json:"social_id" form:"social_id"`
+	Email      string `json:"email" form:"email"`
+	Name       string `json:"name" form:"name"`
+	Password   string `json:"password" form:"password"`
+	Repassword string `json:"repassword" form:"repassword"`
 }
 
 type RegisterUserResponse struct {
@@ -57,11 +60,11 @@ type RegisterUserResponse struct {
 }
 
 type UpdateUserRequest struct {
-	Token    string `json:"token"`
-	Id       int    `json:"id"`
-	Name     string `json:"name"`
-	Location string `json:"location"`
-	Desc     string `json:"desc"`
+	Token    string `json:"token" form:"token"`
+	Id       int    `json:"id" params:"id"`
+	Name     string `json:"name" form:"name"`
+	Location string `json:"location" form:"location"`
+	Desc     string `json:"desc" form:"desc"`
 }
 
 type UpdateUserResponse struct {
@@ -71,8 +74,8 @@ type UpdateUserResponse struct {
 }
 
 type DeleteUserRequest struct {
-	Token string `json:"token"`
-	Id    int    `json:"id"`
+	Token string `json:"token" form:"token"`
+	Id    int    `json:"id" params:"id"`
 }
 
 type DeleteUserResponse struct {
@@ -81,9 +84,9 @@ type DeleteUserResponse struct {
 }
 
 type GetUserRequest struct {
-	Id    int    `json:"id"`
-	Query string `json:"q"`
-	Page  int    `json:"page"`
+	Id    int    `json:"id" params:"id"`
+	Query string `json:"q" params:"query"`
+	Page  int    `json:"page" query:"page" default:"1"`
 	Limit int    `json:"limit"`
 }
 
@@ -0,0 +1,24 @@
+package model
+
+type WebLoginRequest struct {
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	Remember  bool   `json:"remember"`
+	Ip        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+type WebChallengeRequest struct {
+	Token        string `json:"token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+	Remember     bool   `json:"remember"`
+	Ip           string `json:"ip"`
+	UserAgent    string `json:"user_agent"`
+}
+
+type WebLoginResponse struct {
+	User           AuthUser `json:"user"`
+	RememberToken  string   `json:"-"`
+	TwoFactorToken string   `json:"-"`
+}
@@ -4,6 +4,10 @@ type WebResponse struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
+	// Set on the error responses to find the request in the logs
+	RequestID string `json:"request_id,omitempty"`
+	// Set on the unexpected error responses to find the error in the logs
+	ErrorID string `json:"error_id,omitempty"`
 }
 
 type PaginateResponse struct {
@@ -18,5 +22,37 @@ type TokenResponse struct {
 	Alg             string      `json:"alg"`
 	Token           string      `json:"token"`
 	RefreshTokenURL string      `json:"refresh_token_url"`
+	ChallengeURL    string      `json:"challenge_url,omitempty"`
 	Claims          interface{} `json:"claims"`
 }
+
+// Details of the unexpected error responded if APP_DEBUG
+type DebugError struct {
+	Message string       `json:"message"`
+	Type    string       `json:"type"`
+	Stack   []string     `json:"stack,omitempty"`
+	SQL     string       `json:"sql,omitempty"`
+	Request DebugRequest `json:"request"`
+}
+
+type DebugRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Query   map[string]string `json:"query,omitempty"`
+	Form    map[string]string `json:"form,omitempty"`
+}
+
+// Error response of RFC 7807, the request_id, error_id, errors and debug are
+// the extension members
+type ProblemResponse struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+	ErrorID   string            `json:"error_id,omitempty"`
+	Debug     *DebugError       `json:"debug,omitempty"`
+}
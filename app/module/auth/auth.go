@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"govel/app/connection"
+	"govel/app/console"
+	"govel/app/container"
+	"govel/app/entity"
+	"govel/app/hashing"
+	"govel/app/http/controller"
+	"govel/app/module"
+	"govel/app/provider"
+	"govel/app/repository"
+	"govel/app/schedule"
+	"govel/config"
+	"time"
+
+	"github.com/bxcodec/faker/v4"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Users, login, tokens, two factor and web sessions
+type Module struct {
+	module.Base
+}
+
+func (auth Module) Name() string {
+	return "auth"
+}
+
+func (auth Module) Config(env config.Env) config.Map {
+	return config.Map{
+		"sessions": config.Map{
+			// Delete the expired sessions of the database driver every interval
+			"prune_interval": env("SESSION_PRUNE_INTERVAL", "1h"),
+		},
+		"login_attempts": config.Map{
+			// Named connection of the login_attempts table like "audit", the
+			// default connection if empty
+			"connection": env("LOGIN_ATTEMPTS_CONNECTION", ""),
+		},
+	}
+}
+
+func (auth Module) Providers() []provider.ServiceProvider {
+	return []provider.ServiceProvider{
+		&RepositoryServiceProvider{},
+		&AuthServiceProvider{},
+	}
+}
+
+func (auth Module) APIRoutes(api fiber.Router, c *container.Container) {
+	userController := controller.NewUserController()
+	userController.Route(api)
+	personalAccessTokenController := controller.NewPersonalAccessTokenController()
+	personalAccessTokenController.Route(api)
+	twoFactorController := controller.NewTwoFactorController()
+	twoFactorController.Route(api)
+	loginAttemptController := controller.NewLoginAttemptController()
+	loginAttemptController.Route(api)
+}
+
+func (auth Module) WebRoutes(web fiber.Router, c *container.Container) {
+	webAuthController := controller.NewWebAuthController()
+	webAuthController.Route(web)
+}
+
+func (auth Module) Entities() []interface{} {
+	return []interface{}{
+		&entity.User{},
+		&entity.PersonalAccessToken{},
+		&entity.Session{},
+		&entity.LoginAttempt{},
+	}
+}
+
+func (auth Module) Seed(db *gorm.DB) error {
+	hashed := hashing.Default().Make("rahasia")
+	for i := 0; i < 30; i++ {
+		// Suffix the index to keep the unique columns unique
+		email := fmt.Sprintf("%s%d@gmail.com", faker.Word(), i)
+		result := db.Create(&entity.User{
+			Email:      email,
+			EmailIndex: repository.EmailIndex(email),
+			Password:   hashed,
+			Name:       faker.Word(),
+			Nick:       fmt.Sprintf("%s%d", faker.Word(), i),
+			Role:       1,
+			Status:     1,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
+
+func (auth Module) Commands(configuration config.Config) []console.Command {
+	return []console.Command{
+		{
+			Name:        "session:prune",
+			Description: "Delete the expired sessions of the database driver",
+			Handle: func(args []string) error {
+				count, err := PruneSessions(config.NewDatabase(configuration), time.Now())
+				if err == nil {
+					fmt.Printf("Deleted %d expired sessions.\n", count)
+				}
+				return err
+			},
+		},
+	}
+}
+
+func (auth Module) Schedule(schedule *schedule.Schedule, c *container.Container) {
+	configuration := container.Make[config.Config](c)
+	if configuration.Get("session.driver") != "database" {
+		return
+	}
+
+	interval := configuration.GetDuration("auth.sessions.prune_interval", time.Hour)
+	schedule.Every(interval, "session:prune", func(ctx context.Context) error {
+		_, err := PruneSessions(container.Make[*connection.Manager](c).Default().WithContext(ctx), time.Now())
+		return err
+	})
+}
+
+// Delete the sessions expired before now
+func PruneSessions(db *gorm.DB, now time.Time) (int64, error) {
+	result := db.Where("expired_at < ?", now.Unix()).Delete(&entity.Session{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"govel/app/container"
+	"govel/app/hashing"
+	"govel/app/repository"
+	"govel/app/service"
+	"govel/config"
+	"time"
+)
+
+// Bind the user, token, two factor and login services
+type AuthServiceProvider struct{}
+
+func (provider *AuthServiceProvider) Register(c *container.Container) {
+	container.Scoped(c, func(c *container.Container) service.UserService {
+		userRepository := container.Make[repository.UserRepository](c)
+		loginAttemptRepository := container.Make[repository.LoginAttemptRepository](c)
+		return service.NewUserService(&userRepository, &loginAttemptRepository, container.Make[service.LoginThrottle](c), container.Make[hashing.Hasher](c), container.Make[hashing.DummyHash](c))
+	})
+	container.Scoped(c, func(c *container.Container) service.PersonalAccessTokenService {
+		tokenRepository := container.Make[repository.PersonalAccessTokenRepository](c)
+		userRepository := container.Make[repository.UserRepository](c)
+		return service.NewPersonalAccessTokenService(&tokenRepository, &userRepository)
+	})
+	container.Scoped(c, func(c *container.Container) service.TwoFactorService {
+		userRepository := container.Make[repository.UserRepository](c)
+		loginAttemptRepository := container.Make[repository.LoginAttemptRepository](c)
+		issuer := container.Make[config.Config](c).Get("app.name")
+		return service.NewTwoFactorService(&userRepository, &loginAttemptRepository, container.Make[service.LoginThrottle](c), issuer, container.Make[func() time.Time](c))
+	})
+	container.Scoped(c, func(c *container.Container) service.LoginAttemptService {
+		loginAttemptRepository := container.Make[repository.LoginAttemptRepository](c)
+		return service.NewLoginAttemptService(&loginAttemptRepository)
+	})
+	container.Scoped(c, func(c *container.Container) service.WebAuthService {
+		userService := container.Make[service.UserService](c)
+		twoFactorService := container.Make[service.TwoFactorService](c)
+		userRepository := container.Make[repository.UserRepository](c)
+		return service.NewWebAuthService(&userService, &twoFactorService, &userRepository)
+	})
+}
+
+func (provider *AuthServiceProvider) Boot(c *container.Container) error {
+	return nil
+}
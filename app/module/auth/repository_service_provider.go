@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"govel/app/connection"
+	"govel/app/container"
+	"govel/app/repository"
+	"govel/config"
+
+	"gorm.io/gorm"
+)
+
+// Bind the repositories to the database of the request
+type RepositoryServiceProvider struct{}
+
+func (provider *RepositoryServiceProvider) Register(c *container.Container) {
+	container.Scoped(c, func(c *container.Container) repository.UserRepository {
+		return repository.NewUserRepository(container.Make[*gorm.DB](c))
+	})
+	container.Scoped(c, func(c *container.Container) repository.PersonalAccessTokenRepository {
+		return repository.NewPersonalAccessTokenRepository(container.Make[*gorm.DB](c))
+	})
+	container.Scoped(c, func(c *container.Container) repository.LoginAttemptRepository {
+		return repository.NewLoginAttemptRepository(loginAttemptsDatabase(c))
+	})
+}
+
+// Check the connection of the login attempts is configured
+func (provider *RepositoryServiceProvider) Boot(c *container.Container) error {
+	name := container.Make[config.Config](c).Get("auth.login_attempts.connection")
+	if name == "" {
+		return nil
+	}
+	_, err := config.LoadConnectionConfig(container.Make[config.Config](c), name)
+	return err
+}
+
+// The login attempts can be kept on the named connection, scoped to the
+// request context like the default connection so the reads stick to the
+// primary after a write
+func loginAttemptsDatabase(c *container.Container) *gorm.DB {
+	name := container.Make[config.Config](c).Get("auth.login_attempts.connection")
+	if name == "" {
+		return container.Make[*gorm.DB](c)
+	}
+	return container.Make[*connection.Manager](c).Connection(name).WithContext(c.Context())
+}
@@ -0,0 +1,141 @@
+package module
+
+import (
+	"errors"
+	"govel/app/console"
+	"govel/app/container"
+	"govel/app/provider"
+	"govel/app/schedule"
+	"govel/config"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Feature package of the app, bundles everything of a domain so adding the
+// domain only adds the module to bootstrap.Modules. Embed Base to implement
+// only the parts you need.
+type Module interface {
+	// Unique name, also the config section of Config
+	Name() string
+	// Config defaults of the section, the env and the config cache override them
+	Config(env config.Env) config.Map
+	// Service providers of the bindings
+	Providers() []provider.ServiceProvider
+	// Routes under /api
+	APIRoutes(api fiber.Router, c *container.Container)
+	// Routes under /, they have the session started
+	WebRoutes(web fiber.Router, c *container.Container)
+	// Entities to auto migrate
+	Entities() []interface{}
+	// Run after the entities are migrated, e.g. raw indexes
+	Migrate(db *gorm.DB) error
+	// Create fake data
+	Seed(db *gorm.DB) error
+	// Commands of the govel binary
+	Commands(configuration config.Config) []console.Command
+	// Tasks run while the app is running
+	Schedule(schedule *schedule.Schedule, c *container.Container)
+}
+
+type Base struct{}
+
+func (module Base) Config(env config.Env) config.Map {
+	return nil
+}
+
+func (module Base) Providers() []provider.ServiceProvider {
+	return nil
+}
+
+func (module Base) APIRoutes(api fiber.Router, c *container.Container) {}
+
+func (module Base) WebRoutes(web fiber.Router, c *container.Container) {}
+
+func (module Base) Entities() []interface{} {
+	return nil
+}
+
+func (module Base) Migrate(db *gorm.DB) error {
+	return nil
+}
+
+func (module Base) Seed(db *gorm.DB) error {
+	return nil
+}
+
+func (module Base) Commands(configuration config.Config) []console.Command {
+	return nil
+}
+
+func (module Base) Schedule(schedule *schedule.Schedule, c *container.Container) {}
+
+// Add the config defaults of the modules, the values already set are kept
+func LoadConfig(configuration config.Config, modules []Module) {
+	for _, module := range modules {
+		config.Defaults(configuration, module.Name(), module.Config)
+	}
+}
+
+func Providers(modules []Module) []provider.ServiceProvider {
+	providers := []provider.ServiceProvider{}
+	for _, module := range modules {
+		providers = append(providers, module.Providers()...)
+	}
+	return providers
+}
+
+func Commands(configuration config.Config, modules []Module) []console.Command {
+	commands := []console.Command{}
+	for _, module := range modules {
+		commands = append(commands, module.Commands(configuration)...)
+	}
+	return commands
+}
+
+// Auto migrate the entities and run the migrations of the modules in order.
+// Every entity is migrated even if some failed, the errors are joined.
+func Migrate(db *gorm.DB, modules []Module) error {
+	messages := []string{}
+	for _, module := range modules {
+		for _, entity := range module.Entities() {
+			if err := db.AutoMigrate(entity); err != nil {
+				messages = append(messages, wrap(module, err).Error())
+			}
+		}
+		if err := module.Migrate(db); err != nil {
+			messages = append(messages, wrap(module, err).Error())
+		}
+	}
+	if len(messages) > 0 {
+		return errors.New("Migration failed:\n- " + strings.Join(messages, "\n- "))
+	}
+	return nil
+}
+
+func Seed(db *gorm.DB, modules []Module) error {
+	for _, module := range modules {
+		if err := module.Seed(db); err != nil {
+			return wrap(module, err)
+		}
+	}
+	return nil
+}
+
+func wrap(module Module, err error) error {
+	return &Error{Module: module.Name(), Err: err}
+}
+
+type Error struct {
+	Module string
+	Err    error
+}
+
+func (err *Error) Error() string {
+	return err.Module + ": " + err.Err.Error()
+}
+
+func (err *Error) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,100 @@
+package openapi
+
+// Version of the OpenAPI specification of the generated documents
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	Tags       []Tag               `json:"tags,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Operations of a path by the lower case method like "post"
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+	// Schemes required together by name, the scopes are empty
+	Security []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema   *Schema             `json:"schema"`
+	Encoding map[string]Encoding `json:"encoding,omitempty"`
+}
+
+// Encoding of a form field, the arrays are comma separated with the form
+// style without explode
+type Encoding struct {
+	Style   string `json:"style,omitempty"`
+	Explode *bool  `json:"explode,omitempty"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	// One of apiKey, http, mutualTLS, oauth2 or openIdConnect
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	// Name and location of the apiKey, in query, header or cookie
+	Name string `json:"name,omitempty"`
+	In   string `json:"in,omitempty"`
+	// Scheme of the http type like "bearer"
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// JSON schema of OpenAPI 3.1, the type is a string or a list with "null"
+// when nullable
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+}
@@ -0,0 +1,380 @@
+package openapi
+
+import (
+	"fmt"
+	"govel/app/exception"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Generate the document from the routes of the app and the descriptions of
+// their handlers
+type Generator struct {
+	// Descriptions of the handlers, Default() if nil
+	Registry *Registry
+	Info     Info
+	// Path prefix of the documented routes like "/api", every route if empty
+	Prefix string
+	// Path prefixes not documented like the docs
+	Exclude []string
+	// Wrap the response data, e.g. in the response envelope
+	Envelope func(data interface{}) interface{}
+	// Wrap the data of the paginated response
+	Paginated func(data interface{}) interface{}
+	// Error responses by the media type like "application/problem+json"
+	Errors map[string]interface{}
+}
+
+var pathParameter = regexp.MustCompile(`:(\w+)\??`)
+
+func (generator *Generator) Generate(app *fiber.App) *Document {
+	document := &Document{
+		OpenAPI: Version,
+		Info:    generator.Info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas:         map[string]*Schema{},
+			SecuritySchemes: map[string]SecurityScheme{},
+		},
+	}
+	builder := &schemaBuilder{components: document.Components.Schemas}
+	middleware := middlewareRoutes(app)
+	operationIDs := map[string]int{}
+	tags := map[string]bool{}
+
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead || len(route.Handlers) == 0 || !generator.documented(route.Path) {
+			continue
+		}
+		operation := generator.operation(builder, route)
+		operation.Security = generator.security(document, route, middleware)
+
+		// Suffix the duplicated IDs, a handler may serve several routes
+		operationIDs[operation.OperationID]++
+		if count := operationIDs[operation.OperationID]; count > 1 {
+			operation.OperationID += strconv.Itoa(count)
+		}
+		for _, tag := range operation.Tags {
+			if !tags[tag] {
+				tags[tag] = true
+				document.Tags = append(document.Tags, Tag{Name: tag})
+			}
+		}
+
+		path := openAPIPath(route.Path)
+		if document.Paths[path] == nil {
+			document.Paths[path] = PathItem{}
+		}
+		document.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+	return document
+}
+
+func (generator *Generator) registry() *Registry {
+	if generator.Registry == nil {
+		return Default()
+	}
+	return generator.Registry
+}
+
+func (generator *Generator) documented(path string) bool {
+	for _, excluded := range generator.Exclude {
+		if covers(excluded, path) {
+			return false
+		}
+	}
+	return covers(generator.Prefix, path)
+}
+
+func (generator *Generator) operation(builder *schemaBuilder, route fiber.Route) *Operation {
+	handler := route.Handlers[len(route.Handlers)-1]
+	doc, _ := generator.registry().doc(handler)
+	controller, method := handlerName(handler)
+
+	operation := &Operation{
+		OperationID: lowerFirst(controller) + method,
+		Summary:     doc.Summary,
+		Description: doc.Description,
+		Tags:        doc.Tags,
+		Responses:   map[string]Response{},
+	}
+	if operation.Summary == "" {
+		operation.Summary = strings.Join(words(method), " ")
+	}
+	if len(operation.Tags) == 0 && controller != "" {
+		operation.Tags = []string{strings.Join(words(controller), " ")}
+	}
+	generator.parameters(builder, operation, route, doc)
+
+	var data *Schema
+	if generator.Envelope != nil {
+		data = builder.schema(generator.Envelope(doc.Response))
+	} else {
+		data = builder.schema(doc.Response)
+	}
+	if doc.Paginated && generator.Paginated != nil {
+		data = &Schema{OneOf: []*Schema{builder.schema(generator.Paginated(doc.Response)), data}}
+	}
+	operation.Responses["200"] = Response{
+		Description: "OK",
+		Content:     map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: data}},
+	}
+
+	errors := map[string]MediaType{}
+	for mediaType, value := range generator.Errors {
+		errors[mediaType] = MediaType{Schema: builder.ref(value)}
+	}
+	if doc.Validate != nil {
+		operation.Responses["400"] = Response{Description: "Invalid fields", Content: errors}
+	}
+	operation.Responses["default"] = Response{Description: "Error", Content: errors}
+	return operation
+}
+
+// Add the path parameters of the route, and the query and form fields of the
+// request model. The form fields of GET and DELETE are in the query.
+func (generator *Generator) parameters(builder *schemaBuilder, operation *Operation, route fiber.Route, doc Doc) {
+	var request reflect.Type
+	if doc.Request != nil {
+		request = reflect.TypeOf(doc.Request)
+	} else if doc.Validate != nil {
+		request = reflect.TypeOf(doc.Validate).In(0)
+	}
+	invalid := invalidFields(doc.Validate)
+
+	for _, name := range route.Params {
+		schema := &Schema{Type: "string"}
+		if field, ok := taggedField(request, "params", name); ok {
+			schema = builder.of(field.Type, reflect.Value{})
+		}
+		operation.Parameters = append(operation.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	if request == nil || request.Kind() != reflect.Struct {
+		return
+	}
+
+	body := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	encoding := map[string]Encoding{}
+	inQuery := route.Method == fiber.MethodGet || route.Method == fiber.MethodDelete
+	for i := 0; i < request.NumField(); i++ {
+		field := request.Field(i)
+		name, ok := jsonName(field)
+		if name == "" || !ok {
+			name = field.Name
+		}
+		if len(doc.Fields) > 0 && !contains(doc.Fields, name) {
+			continue
+		}
+		schema := builder.of(field.Type, reflect.Value{})
+		if value, ok := field.Tag.Lookup("default"); ok {
+			schema.Default = defaultValue(field.Type, value)
+		}
+		required := invalid[name] && schema.Default == nil
+
+		if query := field.Tag.Get("query"); query != "" {
+			operation.Parameters = append(operation.Parameters, Parameter{Name: query, In: "query", Required: required, Schema: schema})
+		}
+		form := field.Tag.Get("form")
+		if form == "" {
+			continue
+		}
+		if inQuery {
+			operation.Parameters = append(operation.Parameters, Parameter{Name: form, In: "query", Required: required, Schema: schema})
+			continue
+		}
+		body.Properties[form] = schema
+		if required {
+			body.Required = append(body.Required, form)
+		}
+		if schema.Type == "array" {
+			explode := false
+			encoding[form] = Encoding{Style: "form", Explode: &explode}
+		}
+	}
+	if len(body.Properties) == 0 {
+		return
+	}
+	if len(encoding) == 0 {
+		encoding = nil
+	}
+	media := MediaType{Schema: body, Encoding: encoding}
+	operation.RequestBody = &RequestBody{
+		Required: len(body.Required) > 0,
+		Content: map[string]MediaType{
+			fiber.MIMEApplicationForm: media,
+			fiber.MIMEMultipartForm:   media,
+		},
+	}
+}
+
+// Secure the operation by the schemes of its middleware, the middleware of
+// the groups are the use routes covering the route path
+func (generator *Generator) security(document *Document, route fiber.Route, middleware []fiber.Route) []map[string][]string {
+	handlers := []fiber.Handler{}
+	for _, use := range middleware {
+		if use.Method == route.Method && covers(use.Path, route.Path) {
+			handlers = append(handlers, use.Handlers...)
+		}
+	}
+	handlers = append(handlers, route.Handlers[:len(route.Handlers)-1]...)
+
+	requirement := map[string][]string{}
+	for _, handler := range handlers {
+		if name, scheme, ok := generator.registry().scheme(handler); ok {
+			requirement[name] = []string{}
+			document.Components.SecuritySchemes[name] = scheme
+		}
+	}
+	if len(requirement) == 0 {
+		return nil
+	}
+	return []map[string][]string{requirement}
+}
+
+// Fields failing the validation of the empty request by their json name
+func invalidFields(validate interface{}) (fields map[string]bool) {
+	fields = map[string]bool{}
+	if validate == nil {
+		return fields
+	}
+	defer func() {
+		if validationError, ok := recover().(exception.ValidationError); ok {
+			for field := range validationError.Fields {
+				fields[field] = true
+			}
+		}
+	}()
+	function := reflect.ValueOf(validate)
+	function.Call([]reflect.Value{reflect.Zero(function.Type().In(0))})
+	return fields
+}
+
+// Routes of the middleware, the routes of the app without the use filter
+// minus the routes with it
+func middlewareRoutes(app *fiber.App) []fiber.Route {
+	key := func(route fiber.Route) string {
+		key := route.Method + " " + route.Path
+		for _, handler := range route.Handlers {
+			key += fmt.Sprintf(" %x", pointer(handler))
+		}
+		return key
+	}
+	routes := map[string]int{}
+	for _, route := range app.GetRoutes(true) {
+		routes[key(route)]++
+	}
+
+	middleware := []fiber.Route{}
+	for _, route := range app.GetRoutes() {
+		if routes[key(route)] > 0 {
+			routes[key(route)]--
+			continue
+		}
+		middleware = append(middleware, route)
+	}
+	return middleware
+}
+
+func taggedField(request reflect.Type, tag string, name string) (reflect.StructField, bool) {
+	if request == nil || request.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	for i := 0; i < request.NumField(); i++ {
+		if request.Field(i).Tag.Get(tag) == name {
+			return request.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func defaultValue(t reflect.Type, value string) interface{} {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if parsed, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return parsed
+		}
+	case reflect.Bool:
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	case reflect.Slice:
+		return strings.Split(value, ",")
+	}
+	return value
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Check the path is the prefix or under it
+func covers(prefix string, path string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// Convert the fiber path like "/users/:id" to "/users/{id}"
+func openAPIPath(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return pathParameter.ReplaceAllString(path, "{$1}")
+}
+
+// Get the controller and the method of the handler like "User" and "Login"
+// from "govel/app/http/controller.(*UserController).Login-fm"
+func handlerName(handler fiber.Handler) (string, string) {
+	function := runtime.FuncForPC(pointer(handler))
+	if function == nil {
+		return "", ""
+	}
+	name := strings.TrimSuffix(function.Name(), "-fm")
+	name = name[strings.LastIndex(name, "/")+1:]
+	parts := strings.Split(name, ".")
+	if len(parts) == 3 && strings.HasPrefix(parts[1], "(") {
+		controller := strings.Trim(parts[1], "(*)")
+		return strings.TrimSuffix(controller, "Controller"), parts[2]
+	}
+	return "", parts[len(parts)-1]
+}
+
+// Split the camel case name like "RefreshToken" to "Refresh" and "Token"
+func words(name string) []string {
+	result := []string{}
+	runes := []rune(name)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1]) {
+			result = append(result, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		result = append(result, string(runes[start:]))
+	}
+	return result
+}
+
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
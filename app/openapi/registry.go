@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Description of the operation of a handler, the path, the path parameters
+// and the security are read from the route
+type Doc struct {
+	Summary     string
+	Description string
+	// Tags of the operation, the name of the controller if empty
+	Tags []string
+	// Request model, its fields tagged with form, query or params are the
+	// parameters and the default tag is their default value. The parameter
+	// type of Validate if nil.
+	Request interface{}
+	// Json names of the request fields read by the handler, every tagged
+	// field if empty
+	Fields []string
+	// Validation of the request like validation.UserLoginValidate, the
+	// fields invalid on the empty request are required
+	Validate interface{}
+	// Data of the response, its interface fields are described by their
+	// values like model.TokenResponse{Claims: model.LoginUserResponse{}}
+	Response interface{}
+	// The response has the URL of the next page unless it's the last page
+	Paginated bool
+}
+
+// Descriptions of the handlers and the security schemes of the middleware,
+// safe for concurrent use
+type Registry struct {
+	docs     map[uintptr]Doc
+	security map[uintptr]string
+	schemes  map[string]SecurityScheme
+	mutex    sync.RWMutex
+}
+
+var defaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{
+		docs:     map[uintptr]Doc{},
+		security: map[uintptr]string{},
+		schemes:  map[string]SecurityScheme{},
+	}
+}
+
+// Registry of the app, the controllers describe their handlers here
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Describe the handler like controller.Login, the method values of the same
+// method share the description
+func (registry *Registry) Describe(handler fiber.Handler, doc Doc) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.docs[pointer(handler)] = doc
+}
+
+// Mark the routes using the middleware as secured by the scheme
+func (registry *Registry) Secure(middleware fiber.Handler, name string, scheme SecurityScheme) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.security[pointer(middleware)] = name
+	registry.schemes[name] = scheme
+}
+
+func (registry *Registry) doc(handler fiber.Handler) (Doc, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	doc, ok := registry.docs[pointer(handler)]
+	return doc, ok
+}
+
+func (registry *Registry) scheme(middleware fiber.Handler) (string, SecurityScheme, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	name, ok := registry.security[pointer(middleware)]
+	return name, registry.schemes[name], ok
+}
+
+// Describe the handler in the default registry
+func Describe(handler fiber.Handler, doc Doc) {
+	defaultRegistry.Describe(handler, doc)
+}
+
+// Secure the routes using the middleware in the default registry
+func Secure(middleware fiber.Handler, name string, scheme SecurityScheme) {
+	defaultRegistry.Secure(middleware, name, scheme)
+}
+
+// Code pointer of the function, the same for every method value of a method
+func pointer(handler fiber.Handler) uintptr {
+	return reflect.ValueOf(handler).Pointer()
+}
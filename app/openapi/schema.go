@@ -0,0 +1,170 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Build the schemas of the Go values by their json tags, the named structs
+// are the components referenced with $ref
+type schemaBuilder struct {
+	components map[string]*Schema
+}
+
+// Schema of the value, the interface fields are described by their values so
+// the structs having them are inlined
+func (builder *schemaBuilder) schema(value interface{}) *Schema {
+	if value == nil {
+		return &Schema{}
+	}
+	return builder.of(reflect.TypeOf(value), reflect.ValueOf(value))
+}
+
+// Reference of the struct value even if it has interface fields
+func (builder *schemaBuilder) ref(value interface{}) *Schema {
+	t := reflect.TypeOf(value)
+	if _, ok := builder.components[t.Name()]; !ok {
+		builder.components[t.Name()] = builder.object(t, reflect.ValueOf(value))
+	}
+	return &Schema{Ref: "#/components/schemas/" + t.Name()}
+}
+
+// Schema of the type, the value is invalid if unknown
+func (builder *schemaBuilder) of(t reflect.Type, v reflect.Value) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(builder.of(t.Elem(), elem(v)))
+	case reflect.Interface:
+		if v.IsValid() && !v.IsNil() {
+			return builder.of(v.Elem().Type(), v.Elem())
+		}
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		minimum := 0.0
+		return &Schema{Type: "integer", Minimum: &minimum}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		item := reflect.Value{}
+		if v.IsValid() && v.Len() > 0 {
+			item = v.Index(0)
+		}
+		return &Schema{Type: "array", Items: builder.of(t.Elem(), item)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: builder.of(t.Elem(), reflect.Value{})}
+	case reflect.Struct:
+		if t.Name() == "" || hasInterface(t) {
+			return builder.object(t, v)
+		}
+		if _, ok := builder.components[t.Name()]; !ok {
+			// Reserve the name first for the recursive structs
+			component := &Schema{}
+			builder.components[t.Name()] = component
+			*component = *builder.object(t, reflect.Value{})
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+	return &Schema{}
+}
+
+func (builder *schemaBuilder) object(t reflect.Type, v reflect.Value) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	builder.properties(schema, t, v)
+	return schema
+}
+
+// Add the fields as the properties, the fields of the embedded structs
+// without a json name are flattened like encoding/json does
+func (builder *schemaBuilder) properties(schema *Schema, t reflect.Type, v reflect.Value) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := fieldValue(v, i)
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded, embeddedValue := field.Type, value
+			if embedded.Kind() == reflect.Pointer {
+				embedded, embeddedValue = embedded.Elem(), elem(value)
+			}
+			if embedded.Kind() == reflect.Struct {
+				builder.properties(schema, embedded, embeddedValue)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = builder.of(field.Type, value)
+	}
+}
+
+func fieldValue(v reflect.Value, i int) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Value{}
+	}
+	return v.Field(i)
+}
+
+// Name of the field in the json tag, false if skipped with "-"
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, true
+}
+
+func hasInterface(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type.Kind() == reflect.Interface {
+			return true
+		}
+	}
+	return false
+}
+
+func elem(v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.IsNil() {
+		return reflect.Value{}
+	}
+	return v.Elem()
+}
+
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+	}
+	if kind, ok := schema.Type.(string); ok {
+		schema.Type = []string{kind, "null"}
+	}
+	return schema
+}
@@ -0,0 +1,23 @@
+package openapi
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"strings"
+)
+
+//go:embed swagger_ui.html
+var swaggerUIPage string
+
+var swaggerUI = template.Must(template.New("swagger-ui").Parse(swaggerUIPage))
+
+// Render the Swagger UI page of the spec, the scripts and styles are loaded
+// from the assets URL like "https://unpkg.com/swagger-ui-dist@5"
+func SwaggerUI(writer io.Writer, title string, specURL string, assetsURL string) error {
+	return swaggerUI.Execute(writer, map[string]string{
+		"Title":     title,
+		"SpecURL":   specURL,
+		"AssetsURL": strings.TrimSuffix(assetsURL, "/"),
+	})
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"govel/app/connection"
+	"govel/app/container"
+	"govel/app/encryption"
+	"govel/app/hashing"
+	"govel/app/health"
+	"govel/app/httpclient"
+	"govel/app/lifecycle"
+	"govel/app/queue"
+	"govel/app/service"
+	"govel/app/session"
+	"govel/config"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Bind the app services made from the config. The config.Config, the
+// config.Settings, the *fiber.App, the logger, the *prometheus.Registry and
+// the trace.TracerProvider are bound by the bootstrap.
+type AppServiceProvider struct{}
+
+func (provider *AppServiceProvider) Register(c *container.Container) {
+	container.Singleton(c, func(c *container.Container) hashing.Hasher {
+		return config.NewHasher(container.Make[config.Config](c))
+	})
+	// Made on boot by the container validation, not on the first login
+	container.Singleton(c, func(c *container.Container) hashing.DummyHash {
+		return hashing.NewDummyHash(container.Make[hashing.Hasher](c))
+	})
+	container.Singleton(c, func(c *container.Container) encryption.Encrypter {
+		return config.NewEncrypter(container.Make[config.Config](c))
+	})
+	container.Singleton(c, func(c *container.Container) service.LoginThrottle {
+		return config.NewLoginThrottle(container.Make[config.Config](c))
+	})
+	container.Singleton(c, func(c *container.Container) *session.Manager {
+		return config.NewSessionManager(container.Make[config.Config](c), container.Make[*connection.Manager](c).Default())
+	})
+	container.Singleton(c, func(c *container.Container) *queue.Queue {
+		return config.NewQueue(container.Make[config.Config](c))
+	})
+	// Shared client of the outgoing calls, forwards the request ID and the trace
+	container.Singleton(c, func(c *container.Container) *http.Client {
+		return httpclient.New(container.Make[config.Settings](c).App.HTTPClientTimeout, container.Make[trace.TracerProvider](c))
+	})
+	container.Singleton(c, func(c *container.Container) *health.Registry {
+		return config.NewHealthRegistry(container.Make[config.Config](c), container.Make[*connection.Manager](c))
+	})
+
+	// Clock of the TOTP codes, swap it on tests
+	container.Instance(c, time.Now)
+}
+
+func (provider *AppServiceProvider) Boot(c *container.Container) error {
+	// Work the queued jobs in the app process
+	if container.Make[config.Settings](c).Queue.Worker {
+		hooks := container.Make[*lifecycle.Lifecycle](c)
+		hooks.OnBoot("queue", func(ctx context.Context) error {
+			container.Make[*queue.Queue](c).Start()
+			return nil
+		})
+		hooks.OnShutdown("queue", func(ctx context.Context) error {
+			return container.Make[*queue.Queue](c).Stop(ctx)
+		})
+	}
+	return nil
+}
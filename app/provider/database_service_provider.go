@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"govel/app/connection"
+	"govel/app/container"
+	"govel/app/lifecycle"
+	"govel/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// Bind the named connections, the *gorm.DB is the default connection scoped
+// to the request context so reads stick to the primary after a write
+type DatabaseServiceProvider struct{}
+
+func (provider *DatabaseServiceProvider) Register(c *container.Container) {
+	container.Singleton(c, func(c *container.Container) *connection.Manager {
+		return config.NewDatabaseManager(container.Make[config.Config](c), container.Make[*prometheus.Registry](c), container.Make[trace.TracerProvider](c))
+	})
+	container.Scoped(c, func(c *container.Container) *gorm.DB {
+		return container.Make[*connection.Manager](c).Default().WithContext(c.Context())
+	})
+}
+
+// The connections are opened when the lifecycle boots, not before, so the
+// routes can be registered without a database
+func (provider *DatabaseServiceProvider) Boot(c *container.Container) error {
+	settings := container.Make[config.Settings](c)
+	hooks := container.Make[*lifecycle.Lifecycle](c)
+
+	var databases *connection.Manager
+	hooks.OnBoot("database", func(ctx context.Context) error {
+		databases = container.Make[*connection.Manager](c)
+		databases.StartHealthChecks(settings.Database.HealthInterval, settings.Database.HealthTimeout)
+		return nil
+	})
+	hooks.OnShutdown("database", func(ctx context.Context) error {
+		if databases == nil {
+			return nil
+		}
+		return databases.Close()
+	})
+	return nil
+}
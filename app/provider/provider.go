@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"govel/app/container"
+)
+
+// Service provider registers the bindings of a module to the container, then
+// boots it after every provider is registered and the bindings are valid
+type ServiceProvider interface {
+	// Only bind to the container, other bindings may not be registered yet
+	Register(c *container.Container)
+	// Use the bindings, e.g. start workers or register routes
+	Boot(c *container.Container) error
+}
+
+// Core providers of the app, registered before the providers of the modules
+func Providers() []ServiceProvider {
+	return []ServiceProvider{
+		&AppServiceProvider{},
+		&DatabaseServiceProvider{},
+	}
+}
+
+func Register(c *container.Container, providers []ServiceProvider) {
+	for _, provider := range providers {
+		provider.Register(c)
+	}
+}
+
+// Boot the providers in order, stop at the first error
+func Boot(c *container.Container, providers []ServiceProvider) error {
+	for _, provider := range providers {
+		if err := provider.Boot(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"govel/app/container"
+	"govel/app/http/controller"
+	"govel/app/http/middleware"
+	"govel/config"
+	"govel/route"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Register the routes of a module, the API routes are registered before the
+// web middleware so the session and csrf middleware don't run on them
+type RouteRegistrar interface {
+	APIRoutes(api fiber.Router, c *container.Container)
+	WebRoutes(web fiber.Router, c *container.Container)
+}
+
+// Register the routes, the controllers resolve their services from the
+// container scope of the request. Register it after the other providers.
+type RouteServiceProvider struct {
+	Registrars []RouteRegistrar
+}
+
+func (provider *RouteServiceProvider) Register(c *container.Container) {}
+
+func (provider *RouteServiceProvider) Boot(c *container.Container) error {
+	app := container.Make[*fiber.App](c)
+
+	// Start the scope of every request
+	app.Use(middleware.StartScope(c))
+
+	settings := container.Make[config.Settings](c)
+	healthController := controller.NewHealthController(settings.App.Debug, settings.Health.Token)
+	healthController.Route(app)
+	if settings.Metrics.Enabled {
+		metricsController := controller.NewMetricsController(container.Make[*prometheus.Registry](c), settings.Metrics.Path, settings.Metrics.Token)
+		metricsController.Route(app)
+	}
+	if settings.OpenAPI.Enabled {
+		configuration := container.Make[config.Config](c)
+		docsController := controller.NewDocsController(config.NewOpenAPIGenerator(configuration), settings.App.Name, settings.OpenAPI.Path, settings.OpenAPI.UIAssetsURL)
+		docsController.Route(app)
+	}
+	apiRoute := app.Group("/api", middleware.APIMiddleware)
+	route.APIRoute(apiRoute, c)
+	for _, registrar := range provider.Registrars {
+		registrar.APIRoutes(apiRoute, c)
+	}
+
+	webRoute := route.WebRoute(app.Group("/", middleware.WebMiddleware), c)
+	for _, registrar := range provider.Registrars {
+		registrar.WebRoutes(webRoute, c)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"govel/app/requestid"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job pushed to the queue, the request ID of the dispatching request is
+// attached so the logs of the job can be found with the request
+type Job struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Payload      json.RawMessage `json:"payload"`
+	RequestID    string          `json:"request_id,omitempty"`
+	Attempts     int             `json:"attempts"`
+	DispatchedAt time.Time       `json:"dispatched_at"`
+}
+
+// Decode the payload to the value
+func (job Job) Decode(value interface{}) error {
+	return json.Unmarshal(job.Payload, value)
+}
+
+type Handler func(ctx context.Context, job Job) error
+
+// Storage of the pushed jobs
+type Driver interface {
+	Push(ctx context.Context, job Job) error
+	// Wait for the next job until the timeout, nil if there is no job
+	Pop(ctx context.Context, timeout time.Duration) (*Job, error)
+}
+
+type Queue struct {
+	// Nil runs the jobs at once on dispatch
+	driver   Driver
+	handlers map[string]Handler
+	// Times to try a failed job
+	tries  int
+	cancel context.CancelFunc
+	done   chan struct{}
+	mutex  sync.RWMutex
+}
+
+// Make the queue running the jobs at once on dispatch
+func NewSync() *Queue {
+	return New(nil, 1)
+}
+
+func New(driver Driver, tries int) *Queue {
+	if tries < 1 {
+		tries = 1
+	}
+	return &Queue{driver: driver, handlers: map[string]Handler{}, tries: tries}
+}
+
+// Register the handler of the job name
+func (queue *Queue) Handle(name string, handler Handler) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.handlers[name] = handler
+}
+
+// Push the job with the payload encoded as JSON, the request ID of the
+// context is attached to the job
+func (queue *Queue) Dispatch(ctx context.Context, name string, payload interface{}) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	job := Job{
+		ID:           requestid.New(),
+		Name:         name,
+		Payload:      encoded,
+		RequestID:    requestid.FromContext(ctx),
+		DispatchedAt: time.Now(),
+	}
+
+	if queue.driver == nil {
+		return queue.Process(ctx, job)
+	}
+	return queue.driver.Push(ctx, job)
+}
+
+// Run the handler of the job with the request ID of the job in the context
+func (queue *Queue) Process(ctx context.Context, job Job) (err error) {
+	queue.mutex.RLock()
+	handler, ok := queue.handlers[job.Name]
+	queue.mutex.RUnlock()
+	if !ok {
+		return fmt.Errorf("job %q has no handler", job.Name)
+	}
+
+	if job.RequestID != "" {
+		ctx = requestid.WithContext(ctx, job.RequestID)
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// Pop and process the jobs in the background until stopped, the failed job
+// is pushed again until it's tried the configured times
+func (queue *Queue) Start() {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if queue.driver == nil || queue.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queue.cancel = cancel
+	queue.done = make(chan struct{})
+	go func() {
+		defer close(queue.done)
+		for ctx.Err() == nil {
+			job, err := queue.driver.Pop(ctx, time.Second)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Error("Queue pop failed", slog.String("error", err.Error()))
+					time.Sleep(time.Second)
+				}
+				continue
+			}
+			if job != nil {
+				queue.work(ctx, *job)
+			}
+		}
+	}()
+}
+
+func (queue *Queue) work(ctx context.Context, job Job) {
+	job.Attempts++
+	err := queue.Process(ctx, job)
+	if err == nil {
+		return
+	}
+
+	logCtx := requestid.WithContext(ctx, job.RequestID)
+	slog.ErrorContext(logCtx, "Job failed", slog.String("job", job.Name), slog.String("job_id", job.ID), slog.Int("attempts", job.Attempts), slog.String("error", err.Error()))
+	if job.Attempts < queue.tries {
+		if err := queue.driver.Push(context.Background(), job); err != nil {
+			slog.ErrorContext(logCtx, "Job retry failed", slog.String("job_id", job.ID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// Stop popping and wait for the running job until the context is done
+func (queue *Queue) Stop(ctx context.Context) error {
+	queue.mutex.Lock()
+	cancel, done := queue.cancel, queue.done
+	queue.cancel = nil
+	queue.mutex.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	select {
+	case <-done:
+		if closer, ok := queue.driver.(io.Closer); ok {
+			return closer.Close()
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,56 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Driver keeping the jobs in a redis list, pushed with LPUSH and popped with
+// BRPOP
+type RedisDriver struct {
+	client *redis.Client
+	queue  string
+}
+
+func NewRedisDriver(options *redis.Options, queue string) *RedisDriver {
+	return &RedisDriver{client: redis.NewClient(options), queue: queue}
+}
+
+func (driver *RedisDriver) Push(ctx context.Context, job Job) error {
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return driver.client.LPush(ctx, driver.key(), encoded).Err()
+}
+
+func (driver *RedisDriver) Pop(ctx context.Context, timeout time.Duration) (*Job, error) {
+	// Zero timeout blocks forever, wait at least a second
+	reply, err := driver.client.BRPop(ctx, max(timeout, time.Second), driver.key()).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The reply is [key, value]
+	job := Job{}
+	if err := json.Unmarshal([]byte(reply[1]), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Close the connections of the driver
+func (driver *RedisDriver) Close() error {
+	return driver.client.Close()
+}
+
+func (driver *RedisDriver) key() string {
+	return "queues:" + driver.queue
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"govel/app/entity"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	Insert(data entity.LoginAttempt) (attempt entity.LoginAttempt)
+
+	CountFailuresByEmail(email string, since time.Time) (count int64, lastFailedAt *time.Time)
+
+	CountFailuresByIp(ip string, since time.Time) (count int64, lastFailedAt *time.Time)
+
+	FetchAll(email string, limit int, offset int) (attempts []entity.LoginAttempt)
+}
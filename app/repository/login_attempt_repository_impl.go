@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"govel/app/entity"
+	"govel/app/exception"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type loginAttemptRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewLoginAttemptRepository(database *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepositoryImpl{
+		DB: database,
+	}
+}
+
+func (repository *loginAttemptRepositoryImpl) Insert(data entity.LoginAttempt) (attempt entity.LoginAttempt) {
+	result := repository.DB.Create(&data)
+	exception.PanicIfNeeded(result.Error)
+	return data
+}
+
+// Count the failures since the time or the last successful login of the
+// email, whichever is later
+func (repository *loginAttemptRepositoryImpl) CountFailuresByEmail(email string, since time.Time) (count int64, lastFailedAt *time.Time) {
+	var lastSuccess entity.LoginAttempt
+	result := repository.DB.Where("email = ? AND successful = ? AND created_at >= ?", email, true, since).Order("id desc").Limit(1).Find(&lastSuccess)
+	exception.PanicIfNeeded(result.Error)
+	if lastSuccess.ID != 0 {
+		since = lastSuccess.CreatedAt
+	}
+	return repository.countFailures("email", email, since)
+}
+
+// Count the failures since the time, the successful logins don't reset the
+// counter so an attacker can't clear it by logging in to own account
+func (repository *loginAttemptRepositoryImpl) CountFailuresByIp(ip string, since time.Time) (count int64, lastFailedAt *time.Time) {
+	return repository.countFailures("ip", ip, since)
+}
+
+func (repository *loginAttemptRepositoryImpl) FetchAll(email string, limit int, offset int) (attempts []entity.LoginAttempt) {
+	var data []entity.LoginAttempt
+	query := repository.DB.Where("successful = ?", false)
+	if email != "" {
+		query = query.Where("email = ?", email)
+	}
+	result := query.Order("id desc").Limit(limit).Offset(offset).Find(&data)
+	exception.PanicIfNeeded(result.Error)
+	return data
+}
+
+func (repository *loginAttemptRepositoryImpl) countFailures(column string, value string, since time.Time) (count int64, lastFailedAt *time.Time) {
+	result := repository.DB.Model(&entity.LoginAttempt{}).Where(column+" = ? AND successful = ? AND created_at >= ?", value, false, since).Count(&count)
+	exception.PanicIfNeeded(result.Error)
+	if count == 0 {
+		return 0, nil
+	}
+
+	var lastFailure entity.LoginAttempt
+	result = repository.DB.Where(column+" = ? AND successful = ?", value, false).Order("id desc").Limit(1).Find(&lastFailure)
+	exception.PanicIfNeeded(result.Error)
+	return count, &lastFailure.CreatedAt
+}
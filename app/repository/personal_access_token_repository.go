@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"govel/app/entity"
+)
+
+type PersonalAccessTokenRepository interface {
+	Fetch(id uint) (token *entity.PersonalAccessToken)
+
+	FetchAllByUser(userId uint) (tokens []entity.PersonalAccessToken)
+
+	Insert(data entity.PersonalAccessToken) (token entity.PersonalAccessToken)
+
+	Touch(id uint)
+
+	Delete(id uint, userId uint) (deleted bool)
+}
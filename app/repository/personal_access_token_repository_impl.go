@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"errors"
+	"govel/app/entity"
+	"govel/app/exception"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type personalAccessTokenRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(database *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepositoryImpl{
+		DB: database,
+	}
+}
+
+func (repository *personalAccessTokenRepositoryImpl) Fetch(id uint) (token *entity.PersonalAccessToken) {
+	var data entity.PersonalAccessToken
+	result := repository.DB.First(&data, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	exception.PanicIfNeeded(result.Error)
+	return &data
+}
+
+func (repository *personalAccessTokenRepositoryImpl) FetchAllByUser(userId uint) (tokens []entity.PersonalAccessToken) {
+	var data []entity.PersonalAccessToken
+	result := repository.DB.Where("user_id = ?", userId).Order("id desc").Find(&data)
+	exception.PanicIfNeeded(result.Error)
+	return data
+}
+
+func (repository *personalAccessTokenRepositoryImpl) Insert(data entity.PersonalAccessToken) (token entity.PersonalAccessToken) {
+	result := repository.DB.Create(&data)
+	exception.PanicIfNeeded(result.Error)
+	return data
+}
+
+func (repository *personalAccessTokenRepositoryImpl) Touch(id uint) {
+	result := repository.DB.Model(&entity.PersonalAccessToken{}).Where("id = ?", id).Update("last_used_at", time.Now())
+	exception.PanicIfNeeded(result.Error)
+}
+
+func (repository *personalAccessTokenRepositoryImpl) Delete(id uint, userId uint) (deleted bool) {
+	result := repository.DB.Where("user_id = ?", userId).Delete(&entity.PersonalAccessToken{}, id)
+	exception.PanicIfNeeded(result.Error)
+	return result.RowsAffected > 0
+}
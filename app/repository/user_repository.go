@@ -2,6 +2,7 @@ package repository
 
 import (
 	"govel/app/entity"
+	"time"
 )
 
 type UserRepository interface {
@@ -17,5 +18,15 @@ type UserRepository interface {
 
 	Update(data entity.User) (user entity.User)
 
+	UpdatePassword(id uint, password string)
+
+	UpdateRememberToken(id uint, token string)
+
+	UpdateTwoFactor(id uint, secret string, recoveryCodes string, confirmedAt *time.Time)
+
+	// Save the TOTP time step of the accepted code, false if the step is not
+	// after the last used step
+	UseTwoFactorStep(id uint, step int64) bool
+
 	Delete(id uint)
 }
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"errors"
+	"govel/app/cast"
 	"govel/app/entity"
 	"govel/app/exception"
+	"govel/app/helper"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -21,13 +24,17 @@ func NewUserRepository(database *gorm.DB) UserRepository {
 func (repository *userRepositoryImpl) Fetch(id uint) (user *entity.User) {
 	var data entity.User
 	result := repository.DB.First(&data, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil
+	}
 	exception.PanicIfNeeded(result.Error)
 	return &data
 }
 
 func (repository *userRepositoryImpl) FetchByEmail(email string) (user *entity.User) {
 	var data entity.User
-	result := repository.DB.Where("email = ?", email).First(&data)
+	// The users stored before the email was encrypted have no index yet
+	result := repository.DB.Where("email_index = ?", EmailIndex(email)).Or("email_index IS NULL AND LOWER(email) = ?", helper.NormalizeEmail(email)).First(&data)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil
 	}
@@ -50,6 +57,7 @@ func (repository *userRepositoryImpl) FindAll(query string, limit int, offset in
 }
 
 func (repository *userRepositoryImpl) Insert(data entity.User) (user entity.User) {
+	data.EmailIndex = EmailIndex(data.Email)
 	result := repository.DB.Create(&data)
 	exception.PanicIfNeeded(result.Error)
 	return data
@@ -57,12 +65,49 @@ func (repository *userRepositoryImpl) Insert(data entity.User) (user entity.User
 
 func (repository *userRepositoryImpl) Update(data entity.User) (user entity.User) {
 	mData := entity.User{}
+	data.EmailIndex = EmailIndex(data.Email)
 	result := repository.DB.Model(&mData).Where("id = ?", data.ID).Updates(data)
 	exception.PanicIfNeeded(result.Error)
 	return mData
 }
 
+func (repository *userRepositoryImpl) UpdatePassword(id uint, password string) {
+	result := repository.DB.Model(&entity.User{}).Where("id = ?", id).Update("password", password)
+	exception.PanicIfNeeded(result.Error)
+}
+
+func (repository *userRepositoryImpl) UpdateRememberToken(id uint, token string) {
+	result := repository.DB.Model(&entity.User{}).Where("id = ?", id).Update("remember_token", token)
+	exception.PanicIfNeeded(result.Error)
+}
+
+func (repository *userRepositoryImpl) UpdateTwoFactor(id uint, secret string, recoveryCodes string, confirmedAt *time.Time) {
+	// Update with the struct, the map values skip the encrypted serializer
+	result := repository.DB.Model(&entity.User{}).Where("id = ?", id).
+		Select("two_factor_secret", "two_factor_recovery_codes", "two_factor_confirmed_at").
+		Updates(entity.User{
+			TwoFactorSecret:        secret,
+			TwoFactorRecoveryCodes: recoveryCodes,
+			TwoFactorConfirmedAt:   confirmedAt,
+		})
+	exception.PanicIfNeeded(result.Error)
+}
+
+func (repository *userRepositoryImpl) UseTwoFactorStep(id uint, step int64) bool {
+	// Compare and set, so the concurrent requests can't use the same step
+	result := repository.DB.Model(&entity.User{}).Where("id = ? AND two_factor_last_step < ?", id, step).
+		Update("two_factor_last_step", step)
+	exception.PanicIfNeeded(result.Error)
+	return result.RowsAffected > 0
+}
+
 func (repository *userRepositoryImpl) Delete(id uint) {
 	result := repository.DB.Delete(&entity.User{}, id)
 	exception.PanicIfNeeded(result.Error)
 }
+
+// Blind index of the email to find the user, the email is normalized so the
+// case and the surrounding spaces don't matter
+func EmailIndex(email string) string {
+	return cast.BlindIndex(helper.NormalizeEmail(email))
+}
@@ -0,0 +1,54 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// Header of the request ID, read from the request and forwarded on the
+// outgoing calls
+const Header = "X-Request-ID"
+
+// Longest request ID accepted from the client
+const maxLength = 128
+
+type contextKey struct{}
+
+// Generate a random UUID v4
+func New() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		panic(err)
+	}
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:])
+}
+
+// Use the request ID from the client if valid, otherwise generate one
+func Normalize(id string) string {
+	if id == "" || len(id) > maxLength {
+		return New()
+	}
+	for _, char := range id {
+		// Printable ASCII only so the ID is safe in the logs and the headers
+		if char < 0x21 || char > 0x7e {
+			return New()
+		}
+	}
+	return id
+}
+
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// Get the request ID of the context, empty if not set
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,105 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Task run every interval while the app is running
+type Task struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Schedule struct {
+	tasks   []Task
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+	mutex   sync.Mutex
+}
+
+func New() *Schedule {
+	return &Schedule{}
+}
+
+// Run the task every interval, the first run is after the first interval
+func (schedule *Schedule) Every(interval time.Duration, name string, run func(ctx context.Context) error) {
+	schedule.mutex.Lock()
+	defer schedule.mutex.Unlock()
+	schedule.tasks = append(schedule.tasks, Task{Name: name, Interval: interval, Run: run})
+}
+
+func (schedule *Schedule) Tasks() []Task {
+	schedule.mutex.Lock()
+	defer schedule.mutex.Unlock()
+	return append([]Task{}, schedule.tasks...)
+}
+
+// Start the tasks in the background, the failed run is logged and retried on
+// the next interval
+func (schedule *Schedule) Start() {
+	schedule.mutex.Lock()
+	defer schedule.mutex.Unlock()
+	if schedule.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	schedule.cancel = cancel
+	for _, task := range schedule.tasks {
+		schedule.running.Add(1)
+		go func(task Task) {
+			defer schedule.running.Done()
+			ticker := time.NewTicker(task.Interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if err := RunTask(ctx, task); err != nil {
+						slog.Error("Scheduled task failed", slog.String("task", task.Name), slog.String("error", err.Error()))
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(task)
+	}
+}
+
+// Stop the tasks and wait for the running ones until the context is done
+func (schedule *Schedule) Stop(ctx context.Context) error {
+	schedule.mutex.Lock()
+	cancel := schedule.cancel
+	schedule.cancel = nil
+	schedule.mutex.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		schedule.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run the task once, the panic is returned as error
+func RunTask(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return task.Run(ctx)
+}
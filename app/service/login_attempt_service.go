@@ -0,0 +1,7 @@
+package service
+
+import "govel/app/model"
+
+type LoginAttemptService interface {
+	List(request model.GetLoginAttemptRequest) (responses []model.GetLoginAttemptResponse, isNextPage bool)
+}
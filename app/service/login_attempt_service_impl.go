@@ -0,0 +1,59 @@
+package service
+
+import (
+	"govel/app/exception"
+	"govel/app/helper"
+	"govel/app/model"
+	"govel/app/repository"
+	"govel/app/validation"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type loginAttemptServiceImpl struct {
+	LoginAttemptRepository repository.LoginAttemptRepository
+}
+
+func NewLoginAttemptService(loginAttemptRepository *repository.LoginAttemptRepository) LoginAttemptService {
+	return &loginAttemptServiceImpl{
+		LoginAttemptRepository: *loginAttemptRepository,
+	}
+}
+
+func (service *loginAttemptServiceImpl) List(request model.GetLoginAttemptRequest) (responses []model.GetLoginAttemptResponse, isNextPage bool) {
+	// Validate the request data
+	validation.LoginAttemptListValidate(request)
+
+	// Only admin can review the failed login attempts
+	token := helper.ParseECDSAToken(request.Token, jwt.SigningMethodES256)
+	claims := token.Claims.(jwt.MapClaims)
+	if claims["scope"] == model.TwoFactorPendingScope || int(claims["role"].(float64)) == 1 {
+		exception.PanicResponse("Unauthorized")
+	}
+
+	// Get the pagination data, +1 limit to check the next page is exist
+	offset := 0
+	limit := request.Limit + 1
+	if request.Page > 1 {
+		offset = (request.Page * limit) - limit
+	}
+	attempts := service.LoginAttemptRepository.FetchAll(helper.NormalizeEmail(request.Email), limit, offset)
+
+	// Response the data
+	isNextPage = false
+	for i := 0; i < len(attempts); i++ {
+		if i == request.Limit {
+			isNextPage = true
+			break
+		}
+		attempt := attempts[i]
+		responses = append(responses, model.GetLoginAttemptResponse{
+			Id:        attempt.ID,
+			Email:     attempt.Email,
+			Ip:        attempt.Ip,
+			UserAgent: attempt.UserAgent,
+			CreatedAt: attempt.CreatedAt,
+		})
+	}
+	return responses, isNextPage
+}
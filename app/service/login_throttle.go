@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"govel/app/exception"
+	"govel/app/repository"
+	"math"
+	"time"
+)
+
+// Rules of the brute force protection on login. The failures are counted
+// per email and per ip within the decay window, a successful login resets
+// the count of the email only. After the max attempts the lockout is doubled
+// on every next failure until the max lockout.
+type LoginThrottle struct {
+	MaxAttempts   int
+	MaxIpAttempts int
+	Decay         time.Duration
+	Lockout       time.Duration
+	MaxLockout    time.Duration
+}
+
+// Panic with too many requests error if the email or ip is locked out
+func (throttle LoginThrottle) Check(repository repository.LoginAttemptRepository, email string, ip string) {
+	since := time.Now().Add(-throttle.Decay)
+
+	count, lastFailedAt := repository.CountFailuresByEmail(email, since)
+	retryAfter := throttle.retryAfter(count, lastFailedAt, throttle.MaxAttempts)
+
+	count, lastFailedAt = repository.CountFailuresByIp(ip, since)
+	if ipRetryAfter := throttle.retryAfter(count, lastFailedAt, throttle.MaxIpAttempts); ipRetryAfter > retryAfter {
+		retryAfter = ipRetryAfter
+	}
+
+	if retryAfter > 0 {
+		panic(exception.TooManyRequestsError{
+			Message:    fmt.Sprintf("Too many login attempts. Please try again in %d seconds.", int(math.Ceil(retryAfter.Seconds()))),
+			RetryAfter: retryAfter,
+		})
+	}
+}
+
+func (throttle LoginThrottle) retryAfter(count int64, lastFailedAt *time.Time, maxAttempts int) time.Duration {
+	if maxAttempts <= 0 || lastFailedAt == nil || count < int64(maxAttempts) {
+		return 0
+	}
+
+	// Exponential backoff starts from the lockout duration
+	lockout := throttle.Lockout
+	for i := int64(maxAttempts); i < count && lockout < throttle.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > throttle.MaxLockout {
+		lockout = throttle.MaxLockout
+	}
+
+	return time.Until(lastFailedAt.Add(lockout))
+}
@@ -0,0 +1,13 @@
+package service
+
+import "govel/app/model"
+
+type PersonalAccessTokenService interface {
+	Create(request model.CreatePersonalAccessTokenRequest) (response model.CreatePersonalAccessTokenResponse)
+
+	List(request model.GetPersonalAccessTokenRequest) (responses []model.GetPersonalAccessTokenResponse)
+
+	Revoke(request model.DeletePersonalAccessTokenRequest) (response model.DeletePersonalAccessTokenResponse)
+
+	Authenticate(plainTextToken string) (response model.AuthUser)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"govel/app/entity"
+	"govel/app/exception"
+	"govel/app/helper"
+	"govel/app/model"
+	"govel/app/repository"
+	"govel/app/validation"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type personalAccessTokenServiceImpl struct {
+	TokenRepository repository.PersonalAccessTokenRepository
+	UserRepository  repository.UserRepository
+}
+
+func NewPersonalAccessTokenService(tokenRepository *repository.PersonalAccessTokenRepository, userRepository *repository.UserRepository) PersonalAccessTokenService {
+	return &personalAccessTokenServiceImpl{
+		TokenRepository: *tokenRepository,
+		UserRepository:  *userRepository,
+	}
+}
+
+func (service *personalAccessTokenServiceImpl) Create(request model.CreatePersonalAccessTokenRequest) (response model.CreatePersonalAccessTokenResponse) {
+	// Validate the token request data
+	validation.PersonalAccessTokenCreateValidate(request)
+	for _, ability := range request.Abilities {
+		if !helper.HasAbility(request.GrantedAbilities, ability) {
+			panic(exception.ForbiddenError{Message: "Token can't grant the " + ability + " ability it doesn't have."})
+		}
+	}
+
+	// Generate the secret, only the hash is stored
+	secret := helper.RandomString(40)
+	abilities, err := json.Marshal(request.Abilities)
+	exception.PanicIfNeeded(err)
+
+	// Set the expiration date in days if requested
+	var expiresAt *time.Time
+	if request.ExpiresIn > 0 {
+		expired := time.Now().AddDate(0, 0, request.ExpiresIn)
+		expiresAt = &expired
+	}
+
+	// Insert the data
+	token := service.TokenRepository.Insert(entity.PersonalAccessToken{
+		UserID:    request.UserId,
+		Name:      request.Name,
+		Token:     helper.SHA256(secret),
+		Abilities: string(abilities),
+		ExpiresAt: expiresAt,
+	})
+
+	// Response the data, the plain text token is shown once
+	response = model.CreatePersonalAccessTokenResponse{
+		Id:             token.ID,
+		Name:           token.Name,
+		Abilities:      request.Abilities,
+		ExpiresAt:      token.ExpiresAt,
+		PlainTextToken: strconv.FormatUint(uint64(token.ID), 10) + "|" + secret,
+	}
+	return response
+}
+
+func (service *personalAccessTokenServiceImpl) List(request model.GetPersonalAccessTokenRequest) (responses []model.GetPersonalAccessTokenResponse) {
+	// Validate the token request data
+	validation.PersonalAccessTokenListValidate(request)
+
+	// Get the data
+	tokens := service.TokenRepository.FetchAllByUser(request.UserId)
+
+	// Response the data
+	responses = []model.GetPersonalAccessTokenResponse{}
+	for _, token := range tokens {
+		responses = append(responses, model.GetPersonalAccessTokenResponse{
+			Id:         token.ID,
+			Name:       token.Name,
+			Abilities:  decodeAbilities(token.Abilities),
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  token.ExpiresAt,
+			CreatedAt:  token.CreatedAt,
+		})
+	}
+	return responses
+}
+
+func (service *personalAccessTokenServiceImpl) Revoke(request model.DeletePersonalAccessTokenRequest) (response model.DeletePersonalAccessTokenResponse) {
+	// Validate the token request data
+	validation.PersonalAccessTokenDeleteValidate(request)
+
+	// Delete the data owned by the user only
+	if !service.TokenRepository.Delete(uint(request.Id), request.UserId) {
+		exception.PanicResponse("Token not found.")
+	}
+
+	// Response
+	response = model.DeletePersonalAccessTokenResponse{
+		Id:      uint(request.Id),
+		Message: "Token revoked.",
+	}
+	return response
+}
+
+func (service *personalAccessTokenServiceImpl) Authenticate(plainTextToken string) (response model.AuthUser) {
+	// Split the token into id and secret part
+	parts := strings.SplitN(plainTextToken, "|", 2)
+	if len(parts) != 2 {
+		exception.PanicResponse("Token invalid.")
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		exception.PanicResponse("Token invalid.")
+	}
+
+	// Check the token is exist, match and not expired
+	token := service.TokenRepository.Fetch(uint(id))
+	if token == nil || subtle.ConstantTimeCompare([]byte(token.Token), []byte(helper.SHA256(parts[1]))) != 1 {
+		exception.PanicResponse("Token invalid.")
+	}
+	if token.ExpiresAt != nil && token.ExpiresAt.Before(time.Now()) {
+		exception.PanicResponse("Token expired.")
+	}
+
+	// Check the token owner is still exist
+	user := service.UserRepository.Fetch(token.UserID)
+	if user == nil {
+		exception.PanicResponse("Token invalid.")
+	}
+
+	// Save the last used timestamp
+	service.TokenRepository.Touch(token.ID)
+
+	// Response the authenticated user
+	response = model.AuthUser{
+		Id:        user.ID,
+		Role:      user.Role,
+		Guard:     "token",
+		TokenId:   token.ID,
+		Abilities: decodeAbilities(token.Abilities),
+	}
+	return response
+}
+
+func decodeAbilities(abilities string) []string {
+	result := []string{}
+	if abilities == "" {
+		return result
+	}
+	err := json.Unmarshal([]byte(abilities), &result)
+	exception.PanicIfNeeded(err)
+	return result
+}
@@ -0,0 +1,17 @@
+package service
+
+import "govel/app/model"
+
+type TwoFactorService interface {
+	Enable(request model.EnableTwoFactorRequest) (response model.EnableTwoFactorResponse)
+
+	Confirm(request model.ConfirmTwoFactorRequest) (response model.TwoFactorStatusResponse)
+
+	Disable(request model.ConfirmTwoFactorRequest) (response model.TwoFactorStatusResponse)
+
+	RegenerateRecoveryCodes(request model.RecoveryCodesTwoFactorRequest) (response model.RecoveryCodesTwoFactorResponse)
+
+	Challenge(request model.ChallengeTwoFactorRequest) (response model.LoginUserResponse)
+
+	Reset(request model.ResetTwoFactorRequest) (response model.TwoFactorStatusResponse)
+}
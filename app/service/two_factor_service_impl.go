@@ -0,0 +1,299 @@
+package service
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"govel/app/entity"
+	"govel/app/exception"
+	"govel/app/helper"
+	"govel/app/model"
+	"govel/app/repository"
+	"govel/app/validation"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Number of the generated recovery codes
+const recoveryCodesCount = 8
+
+type twoFactorServiceImpl struct {
+	UserRepository         repository.UserRepository
+	LoginAttemptRepository repository.LoginAttemptRepository
+	LoginThrottle          LoginThrottle
+	Issuer                 string
+	Clock                  func() time.Time
+}
+
+// The clock is used to validate the TOTP code, pass time.Now except on tests.
+// The challenge failures are throttled like the login failures.
+func NewTwoFactorService(userRepository *repository.UserRepository, loginAttemptRepository *repository.LoginAttemptRepository, loginThrottle LoginThrottle, issuer string, clock func() time.Time) TwoFactorService {
+	return &twoFactorServiceImpl{
+		UserRepository:         *userRepository,
+		LoginAttemptRepository: *loginAttemptRepository,
+		LoginThrottle:          loginThrottle,
+		Issuer:                 issuer,
+		Clock:                  clock,
+	}
+}
+
+func (service *twoFactorServiceImpl) Enable(request model.EnableTwoFactorRequest) (response model.EnableTwoFactorResponse) {
+	// Validate the request data
+	validation.TwoFactorEnableValidate(request)
+
+	// Get the user from the token
+	user := service.userFromToken(request.Token, "")
+	if user.TwoFactorConfirmedAt != nil {
+		exception.PanicResponse("Two factor authentication already enabled.")
+	}
+
+	// Save the unconfirmed secret and the recovery codes
+	secret := helper.TOTPGenerateSecret()
+	codes, hashed := generateRecoveryCodes()
+	service.UserRepository.UpdateTwoFactor(user.ID, secret, hashed, nil)
+
+	// Response the data, the recovery codes are shown once
+	response = model.EnableTwoFactorResponse{
+		Secret:        secret,
+		URI:           helper.TOTPURI(service.Issuer, user.Email, secret),
+		RecoveryCodes: codes,
+	}
+	return response
+}
+
+func (service *twoFactorServiceImpl) Confirm(request model.ConfirmTwoFactorRequest) (response model.TwoFactorStatusResponse) {
+	// Validate the request data
+	validation.TwoFactorConfirmValidate(request)
+
+	// Get the user from the token
+	user := service.userFromToken(request.Token, "")
+	if user.TwoFactorSecret == "" {
+		exception.PanicResponse("Two factor authentication is not enabled.")
+	}
+	if user.TwoFactorConfirmedAt != nil {
+		exception.PanicResponse("Two factor authentication already confirmed.")
+	}
+
+	// Check the code generated by the authenticator app, it can't be used
+	// again for the challenge
+	service.verifyCode(user, request)
+
+	// Confirm the two factor authentication
+	confirmedAt := service.Clock()
+	service.UserRepository.UpdateTwoFactor(user.ID, user.TwoFactorSecret, user.TwoFactorRecoveryCodes, &confirmedAt)
+
+	// Response
+	response = model.TwoFactorStatusResponse{
+		Id:      user.ID,
+		Enabled: true,
+		Message: "Two factor authentication enabled.",
+	}
+	return response
+}
+
+func (service *twoFactorServiceImpl) Disable(request model.ConfirmTwoFactorRequest) (response model.TwoFactorStatusResponse) {
+	// Validate the request data
+	validation.TwoFactorConfirmValidate(request)
+
+	// Get the user from the token
+	user := service.userFromToken(request.Token, "")
+	if user.TwoFactorConfirmedAt == nil {
+		exception.PanicResponse("Two factor authentication is not enabled.")
+	}
+
+	// Check the code generated by the authenticator app, a code seen before
+	// can't turn it off
+	service.verifyCode(user, request)
+
+	// Remove the secret and recovery codes
+	service.UserRepository.UpdateTwoFactor(user.ID, "", "", nil)
+
+	// Response
+	response = model.TwoFactorStatusResponse{
+		Id:      user.ID,
+		Enabled: false,
+		Message: "Two factor authentication disabled.",
+	}
+	return response
+}
+
+func (service *twoFactorServiceImpl) RegenerateRecoveryCodes(request model.RecoveryCodesTwoFactorRequest) (response model.RecoveryCodesTwoFactorResponse) {
+	// Validate the request data
+	validation.TwoFactorRecoveryCodesValidate(request)
+
+	// Get the user from the token
+	user := service.userFromToken(request.Token, "")
+	if user.TwoFactorSecret == "" {
+		exception.PanicResponse("Two factor authentication is not enabled.")
+	}
+
+	// Replace the recovery codes
+	codes, hashed := generateRecoveryCodes()
+	service.UserRepository.UpdateTwoFactor(user.ID, user.TwoFactorSecret, hashed, user.TwoFactorConfirmedAt)
+
+	// Response the data, the recovery codes are shown once
+	response = model.RecoveryCodesTwoFactorResponse{
+		RecoveryCodes: codes,
+	}
+	return response
+}
+
+func (service *twoFactorServiceImpl) Challenge(request model.ChallengeTwoFactorRequest) (response model.LoginUserResponse) {
+	// Validate the request data
+	validation.TwoFactorChallengeValidate(request)
+
+	// Get the user from the pending token issued by login
+	user := service.userFromToken(request.Token, model.TwoFactorPendingScope)
+	if user.TwoFactorConfirmedAt == nil {
+		exception.PanicResponse("Token invalid.")
+	}
+
+	// Check the user and ip are not locked out, the failures count to the
+	// login throttle of the email
+	email := helper.NormalizeEmail(user.Email)
+	service.LoginThrottle.Check(service.LoginAttemptRepository, email, request.Ip)
+
+	message := ""
+	if request.Code != "" {
+		// Check the code generated by the authenticator app, each time step
+		// can be used once only
+		step, ok := helper.TOTPVerify(user.TwoFactorSecret, request.Code, service.Clock(), user.TwoFactorLastStep)
+		if !ok || !service.UserRepository.UseTwoFactorStep(user.ID, step) {
+			message = "Two factor code invalid."
+		}
+	} else {
+		// Check the recovery code, each code can be used once only
+		remaining, ok := useRecoveryCode(user.TwoFactorRecoveryCodes, request.RecoveryCode)
+		if ok {
+			service.UserRepository.UpdateTwoFactor(user.ID, user.TwoFactorSecret, remaining, user.TwoFactorConfirmedAt)
+		} else {
+			message = "Recovery code invalid."
+		}
+	}
+
+	// Record the attempt, the password was checked by login before
+	service.LoginAttemptRepository.Insert(entity.LoginAttempt{
+		Email:      email,
+		Ip:         request.Ip,
+		UserAgent:  request.UserAgent,
+		Successful: message == "",
+	})
+	if message != "" {
+		exception.PanicResponse(message)
+	}
+
+	// Response the data
+	response = model.LoginUserResponse{
+		Id:       user.ID,
+		SocialId: user.SocialId,
+		Email:    user.Email,
+		Nick:     user.Nick,
+		Name:     user.Name,
+		Pic:      user.Pic,
+		Location: user.Location,
+		Desc:     user.Desc,
+		Role:     user.Role,
+	}
+	return response
+}
+
+func (service *twoFactorServiceImpl) Reset(request model.ResetTwoFactorRequest) (response model.TwoFactorStatusResponse) {
+	// Validate the request data
+	validation.TwoFactorResetValidate(request)
+
+	// Only admin can reset the two factor authentication of a user
+	admin := service.userFromToken(request.Token, "")
+	if admin.Role == 1 {
+		exception.PanicResponse("Unauthorized")
+	}
+
+	user := service.UserRepository.Fetch(uint(request.Id))
+	if user == nil {
+		exception.PanicResponse("User not found.")
+	}
+
+	// Remove the secret and recovery codes
+	service.UserRepository.UpdateTwoFactor(user.ID, "", "", nil)
+
+	// Response
+	response = model.TwoFactorStatusResponse{
+		Id:      user.ID,
+		Enabled: false,
+		Message: "Two factor authentication reset.",
+	}
+	return response
+}
+
+// Parse the token and fetch the user, the token scope must match
+// Check the TOTP code of the user once per time step. The user and ip
+// locked out by the login throttle are rejected and the attempt counts to it
+// so the code can't be brute forced.
+func (service *twoFactorServiceImpl) verifyCode(user *entity.User, request model.ConfirmTwoFactorRequest) {
+	email := helper.NormalizeEmail(user.Email)
+	service.LoginThrottle.Check(service.LoginAttemptRepository, email, request.Ip)
+
+	step, ok := helper.TOTPVerify(user.TwoFactorSecret, request.Code, service.Clock(), user.TwoFactorLastStep)
+	ok = ok && service.UserRepository.UseTwoFactorStep(user.ID, step)
+	service.LoginAttemptRepository.Insert(entity.LoginAttempt{
+		Email:      email,
+		Ip:         request.Ip,
+		UserAgent:  request.UserAgent,
+		Successful: ok,
+	})
+	if !ok {
+		exception.PanicResponse("Two factor code invalid.")
+	}
+}
+
+func (service *twoFactorServiceImpl) userFromToken(token string, scope string) *entity.User {
+	jwtToken := helper.ParseECDSAToken(token, jwt.SigningMethodES256)
+	claims := jwtToken.Claims.(jwt.MapClaims)
+	tokenScope, _ := claims["scope"].(string)
+	id, _ := claims["id"].(float64)
+	if !jwtToken.Valid || tokenScope != scope {
+		exception.PanicResponse("Token invalid.")
+	}
+
+	user := service.UserRepository.Fetch(uint(id))
+	if user == nil {
+		exception.PanicResponse("Token invalid.")
+	}
+	return user
+}
+
+// Generate the plain recovery codes and the json of the hashed codes
+func generateRecoveryCodes() (codes []string, hashed string) {
+	hashes := []string{}
+	for i := 0; i < recoveryCodesCount; i++ {
+		code := strings.ToLower(helper.RandomString(5) + "-" + helper.RandomString(5))
+		codes = append(codes, code)
+		hashes = append(hashes, helper.SHA256(code))
+	}
+	encoded, err := json.Marshal(hashes)
+	exception.PanicIfNeeded(err)
+	return codes, string(encoded)
+}
+
+// Remove the used recovery code and return the json of the remaining codes
+func useRecoveryCode(recoveryCodes string, code string) (remaining string, ok bool) {
+	hashes := []string{}
+	if recoveryCodes != "" {
+		err := json.Unmarshal([]byte(recoveryCodes), &hashes)
+		exception.PanicIfNeeded(err)
+	}
+
+	hashed := helper.SHA256(strings.ToLower(strings.TrimSpace(code)))
+	left := []string{}
+	for _, hash := range hashes {
+		if !ok && subtle.ConstantTimeCompare([]byte(hash), []byte(hashed)) == 1 {
+			ok = true
+			continue
+		}
+		left = append(left, hash)
+	}
+
+	encoded, err := json.Marshal(left)
+	exception.PanicIfNeeded(err)
+	return string(encoded), ok
+}
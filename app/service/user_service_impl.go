@@ -3,23 +3,37 @@ package service
 import (
 	"govel/app/entity"
 	"govel/app/exception"
+	"govel/app/hashing"
 	"govel/app/helper"
 	"govel/app/model"
 	"govel/app/repository"
 	"govel/app/validation"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/mintance/go-uniqid"
-	"golang.org/x/crypto/bcrypt"
 )
 
+// Message of every failed login, it must not leak whether the email exists
+const failedLoginMessage = "These credentials do not match our records."
+
 type userServiceImpl struct {
-	UserRepository repository.UserRepository
+	UserRepository         repository.UserRepository
+	LoginAttemptRepository repository.LoginAttemptRepository
+	LoginThrottle          LoginThrottle
+	Hasher                 hashing.Hasher
+	// Hash compared when the email is not registered, so the response
+	// time is the same as a wrong password
+	DummyHash hashing.DummyHash
 }
 
-func NewUserService(userRepository *repository.UserRepository) UserService {
+func NewUserService(userRepository *repository.UserRepository, loginAttemptRepository *repository.LoginAttemptRepository, loginThrottle LoginThrottle, hasher hashing.Hasher, dummyHash hashing.DummyHash) UserService {
 	return &userServiceImpl{
-		UserRepository: *userRepository,
+		UserRepository:         *userRepository,
+		LoginAttemptRepository: *loginAttemptRepository,
+		LoginThrottle:          loginThrottle,
+		Hasher:                 hasher,
+		DummyHash:              dummyHash,
 	}
 }
 
@@ -30,8 +44,13 @@ func (service *userServiceImpl) RefreshToken(request model.RefreshTokenUserReque
 	// Parsing the token
 	token := helper.ParseECDSAToken(request.Token, jwt.SigningMethodES256)
 
-	// Check user is exist
+	// Check the token is not waiting for the two factor challenge
 	claims := token.Claims.(jwt.MapClaims)
+	if claims["scope"] == model.TwoFactorPendingScope {
+		exception.PanicResponse("Token invalid.")
+	}
+
+	// Check user is exist
 	user := service.UserRepository.FetchByEmail(claims["email"].(string))
 	if user == nil {
 		exception.PanicResponse("Token invalid.")
@@ -56,16 +75,38 @@ func (service *userServiceImpl) Login(request model.LoginUserRequest) (response
 	// Validate the user request data
 	validation.UserLoginValidate(request)
 
-	// Check user is exist
+	// Check the email and ip are not locked out
+	email := helper.NormalizeEmail(request.Email)
+	service.LoginThrottle.Check(service.LoginAttemptRepository, email, request.Ip)
+
+	// Check the hash password is correct, compare with the dummy hash if
+	// the user is not exist to keep the same timing
 	user := service.UserRepository.FetchByEmail(request.Email)
-	if user == nil {
-		exception.PanicResponse("Email not registered.")
+	hash := ""
+	if user != nil && user.Password != "" {
+		hash = user.Password
+	} else {
+		hash = string(service.DummyHash)
+	}
+	validPassword := service.Hasher.Check(request.Password, hash)
+
+	// Record the attempt to be reviewed by admin, the login waiting for the
+	// two factor challenge is recorded by the challenge
+	if user == nil || !validPassword || user.TwoFactorConfirmedAt == nil {
+		service.LoginAttemptRepository.Insert(entity.LoginAttempt{
+			Email:      email,
+			Ip:         request.Ip,
+			UserAgent:  request.UserAgent,
+			Successful: user != nil && validPassword,
+		})
+	}
+	if user == nil || !validPassword {
+		exception.PanicResponse(failedLoginMessage)
 	}
 
-	// Check the hash password is correct
-	errPassword := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password))
-	if errPassword != nil {
-		exception.PanicResponse("Email or password doesn't match.")
+	// Upgrade the hash when the hashing algorithm or cost changed
+	if service.Hasher.NeedsRehash(user.Password) {
+		service.UserRepository.UpdatePassword(user.ID, service.Hasher.Make(request.Password))
 	}
 
 	// Response the data
@@ -80,6 +121,12 @@ func (service *userServiceImpl) Login(request model.LoginUserRequest) (response
 		Desc:     user.Desc,
 		Role:     user.Role,
 	}
+
+	// Issue short lived token for the two factor challenge if enabled
+	if user.TwoFactorConfirmedAt != nil {
+		response.Scope = model.TwoFactorPendingScope
+		response.ExpiresAt = time.Now().Add(5 * time.Minute).Unix()
+	}
 	return response
 }
 
@@ -94,16 +141,15 @@ func (service *userServiceImpl) Register(request model.RegisterUserRequest) (res
 	}
 
 	// Hasing the password
-	password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
-	exception.PanicIfNeeded(err)
+	password := service.Hasher.Make(request.Password)
 
 	// Insert the data
 	data := entity.User{
 		SocialId: request.SocialId,
 		Email:    request.Email,
-		Nick:     uniqid.New(uniqid.Params{Prefix: "govel", MoreEntropy: false}),
+		Nick:     uniqid.New(uniqid.Params{Prefix: "govel", MoreEntropy: true}),
 		Name:     request.Name,
-		Password: string(password),
+		Password: password,
 	}
 	user := service.UserRepository.Insert(data)
 
@@ -127,6 +173,9 @@ func (service *userServiceImpl) Single(request model.GetUserRequest) (response m
 
 	// Get the data
 	user := service.UserRepository.Fetch(uint(request.Id))
+	if user == nil {
+		exception.PanicResponse("User not found.")
+	}
 
 	// Response the data
 	response = model.GetUserResponse{
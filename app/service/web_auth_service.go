@@ -0,0 +1,15 @@
+package service
+
+import "govel/app/model"
+
+type WebAuthService interface {
+	Login(request model.WebLoginRequest) (response model.WebLoginResponse)
+
+	Challenge(request model.WebChallengeRequest) (response model.WebLoginResponse)
+
+	Authenticate(userId uint) (response *model.AuthUser)
+
+	Recall(rememberToken string) (response *model.AuthUser)
+
+	Logout(userId uint)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"crypto/subtle"
+	"govel/app/entity"
+	"govel/app/helper"
+	"govel/app/model"
+	"govel/app/repository"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type webAuthServiceImpl struct {
+	UserService      UserService
+	TwoFactorService TwoFactorService
+	UserRepository   repository.UserRepository
+}
+
+func NewWebAuthService(userService *UserService, twoFactorService *TwoFactorService, userRepository *repository.UserRepository) WebAuthService {
+	return &webAuthServiceImpl{
+		UserService:      *userService,
+		TwoFactorService: *twoFactorService,
+		UserRepository:   *userRepository,
+	}
+}
+
+func (service *webAuthServiceImpl) Login(request model.WebLoginRequest) (response model.WebLoginResponse) {
+	// Check the credentials, same rules as the api login
+	user := service.UserService.Login(model.LoginUserRequest{
+		Email:     request.Email,
+		Password:  request.Password,
+		Ip:        request.Ip,
+		UserAgent: request.UserAgent,
+	})
+
+	// The user must pass the two factor challenge before logged in
+	if user.Scope == model.TwoFactorPendingScope {
+		response = model.WebLoginResponse{
+			TwoFactorToken: helper.MakeECDSAToken(&user, jwt.SigningMethodES256),
+		}
+		return response
+	}
+
+	return service.loggedIn(user, request.Remember)
+}
+
+func (service *webAuthServiceImpl) Challenge(request model.WebChallengeRequest) (response model.WebLoginResponse) {
+	// Check the code with the pending token saved on login
+	user := service.TwoFactorService.Challenge(model.ChallengeTwoFactorRequest{
+		Token:        request.Token,
+		Code:         request.Code,
+		RecoveryCode: request.RecoveryCode,
+		Ip:           request.Ip,
+		UserAgent:    request.UserAgent,
+	})
+
+	return service.loggedIn(user, request.Remember)
+}
+
+func (service *webAuthServiceImpl) Authenticate(userId uint) (response *model.AuthUser) {
+	user := service.UserRepository.Fetch(userId)
+	if user == nil {
+		return nil
+	}
+	return sessionAuthUser(user)
+}
+
+func (service *webAuthServiceImpl) Recall(rememberToken string) (response *model.AuthUser) {
+	// Remember token has format "{id}|{token}"
+	parts := strings.SplitN(rememberToken, "|", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	user := service.UserRepository.Fetch(uint(id))
+	if user == nil || user.RememberToken == "" || subtle.ConstantTimeCompare([]byte(user.RememberToken), []byte(helper.SHA256(parts[1]))) != 1 {
+		return nil
+	}
+	return sessionAuthUser(user)
+}
+
+func (service *webAuthServiceImpl) Logout(userId uint) {
+	// Remove the remember token so the cookie can't be used anymore
+	service.UserRepository.UpdateRememberToken(userId, "")
+}
+
+func (service *webAuthServiceImpl) loggedIn(user model.LoginUserResponse, remember bool) (response model.WebLoginResponse) {
+	response = model.WebLoginResponse{
+		User: model.AuthUser{
+			Id:        user.Id,
+			Role:      user.Role,
+			Guard:     "session",
+			Abilities: []string{"*"},
+		},
+	}
+
+	// Save the hashed remember token, the plain token is sent as cookie
+	if remember {
+		token := helper.RandomString(60)
+		service.UserRepository.UpdateRememberToken(user.Id, helper.SHA256(token))
+		response.RememberToken = strconv.FormatUint(uint64(user.Id), 10) + "|" + token
+	}
+	return response
+}
+
+func sessionAuthUser(user *entity.User) *model.AuthUser {
+	return &model.AuthUser{
+		Id:        user.ID,
+		Role:      user.Role,
+		Guard:     "session",
+		Abilities: []string{"*"},
+	}
+}
@@ -0,0 +1,69 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"govel/app/exception"
+	"strings"
+	"time"
+)
+
+type cookiePayload struct {
+	Data      map[string]string `json:"data"`
+	ExpiredAt int64             `json:"expired_at"`
+}
+
+type cookieStore struct {
+	key []byte
+}
+
+// Keep the whole session in the cookie signed by the key. The payload is
+// signed, not encrypted, so don't put secrets in the session.
+func NewCookieStore(key string) Store {
+	if key == "" {
+		exception.PanicIfNeeded("APP_KEY is required by the cookie session driver, run ./govel key:generate")
+	}
+	return &cookieStore{
+		key: []byte(key),
+	}
+}
+
+func (store *cookieStore) Read(value string) map[string]string {
+	parts := strings.SplitN(value, ".", 2)
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(store.sign(parts[0]))) {
+		return nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil
+	}
+	payload := cookiePayload{}
+	if json.Unmarshal(decoded, &payload) != nil || time.Unix(payload.ExpiredAt, 0).Before(time.Now()) {
+		return nil
+	}
+	return payload.Data
+}
+
+func (store *cookieStore) Write(id string, data map[string]string, lifetime time.Duration) string {
+	encoded, err := json.Marshal(cookiePayload{
+		Data:      data,
+		ExpiredAt: time.Now().Add(lifetime).Unix(),
+	})
+	exception.PanicIfNeeded(err)
+
+	value := base64.RawURLEncoding.EncodeToString(encoded)
+	return value + "." + store.sign(value)
+}
+
+func (store *cookieStore) Destroy(id string) {
+	// Nothing to destroy, the cookie is expired by the manager
+}
+
+func (store *cookieStore) sign(value string) string {
+	mac := hmac.New(sha256.New, store.key)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
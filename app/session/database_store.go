@@ -0,0 +1,69 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"govel/app/entity"
+	"govel/app/exception"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type databaseStore struct {
+	DB *gorm.DB
+}
+
+// Keep the sessions in the sessions table
+func NewDatabaseStore(database *gorm.DB) Store {
+	return &databaseStore{
+		DB: database,
+	}
+}
+
+func (store *databaseStore) Read(value string) map[string]string {
+	var data entity.Session
+	result := store.DB.Where("id = ?", value).First(&data)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	exception.PanicIfNeeded(result.Error)
+
+	if time.Unix(data.ExpiredAt, 0).Before(time.Now()) {
+		store.Destroy(value)
+		return nil
+	}
+
+	payload := map[string]string{}
+	err := json.Unmarshal([]byte(data.Payload), &payload)
+	exception.PanicIfNeeded(err)
+	return payload
+}
+
+func (store *databaseStore) Write(id string, data map[string]string, lifetime time.Duration) string {
+	payload, err := json.Marshal(data)
+	exception.PanicIfNeeded(err)
+
+	// Save the user id to find the sessions of a user
+	var userId *uint
+	if value, err := strconv.ParseUint(data[AuthKey], 10, 64); err == nil {
+		id := uint(value)
+		userId = &id
+	}
+
+	result := store.DB.Save(&entity.Session{
+		ID:           id,
+		UserID:       userId,
+		Payload:      string(payload),
+		LastActivity: time.Now().Unix(),
+		ExpiredAt:    time.Now().Add(lifetime).Unix(),
+	})
+	exception.PanicIfNeeded(result.Error)
+	return id
+}
+
+func (store *databaseStore) Destroy(id string) {
+	result := store.DB.Delete(&entity.Session{}, "id = ?", id)
+	exception.PanicIfNeeded(result.Error)
+}
@@ -0,0 +1,59 @@
+package session
+
+import (
+	"govel/app/helper"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Manager struct {
+	Store    Store
+	Cookie   string
+	Lifetime time.Duration
+	Path     string
+	Domain   string
+	Secure   bool
+}
+
+// Load the session from the request cookie, start a new one if not exist
+func (manager *Manager) Start(c *fiber.Ctx) *Session {
+	value := c.Cookies(manager.Cookie)
+	if value != "" {
+		if data := manager.Store.Read(value); data != nil {
+			return &Session{id: value, data: data}
+		}
+	}
+	return &Session{id: helper.RandomString(40), data: map[string]string{}}
+}
+
+// Write the session to the store and send the cookie
+func (manager *Manager) Save(c *fiber.Ctx, session *Session) {
+	for _, id := range session.previousIds {
+		manager.Store.Destroy(id)
+	}
+	session.previousIds = nil
+
+	if session.destroyed {
+		manager.Store.Destroy(session.id)
+		manager.SetCookie(c, manager.Cookie, "", -time.Hour)
+		return
+	}
+
+	value := manager.Store.Write(session.id, session.data, manager.Lifetime)
+	manager.SetCookie(c, manager.Cookie, value, manager.Lifetime)
+}
+
+// Send http only cookie with the session cookie settings
+func (manager *Manager) SetCookie(c *fiber.Ctx, name string, value string, lifetime time.Duration) {
+	c.Cookie(&fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     manager.Path,
+		Domain:   manager.Domain,
+		Expires:  time.Now().Add(lifetime),
+		Secure:   manager.Secure,
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
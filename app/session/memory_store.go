@@ -0,0 +1,72 @@
+package session
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryItem struct {
+	data      map[string]string
+	expiredAt time.Time
+}
+
+type memoryStore struct {
+	mutex sync.Mutex
+	items map[string]memoryItem
+}
+
+// Keep the sessions in process memory, use it for development or test only
+func NewMemoryStore() Store {
+	return &memoryStore{
+		items: map[string]memoryItem{},
+	}
+}
+
+func (store *memoryStore) Read(value string) map[string]string {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	item, ok := store.items[value]
+	if !ok {
+		return nil
+	}
+	if item.expiredAt.Before(time.Now()) {
+		delete(store.items, value)
+		return nil
+	}
+	return copyData(item.data)
+}
+
+func (store *memoryStore) Write(id string, data map[string]string, lifetime time.Duration) string {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	// Cleanup expired sessions
+	now := time.Now()
+	for key, item := range store.items {
+		if item.expiredAt.Before(now) {
+			delete(store.items, key)
+		}
+	}
+
+	store.items[id] = memoryItem{
+		data:      copyData(data),
+		expiredAt: now.Add(lifetime),
+	}
+	return id
+}
+
+func (store *memoryStore) Destroy(id string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.items, id)
+}
+
+func copyData(data map[string]string) map[string]string {
+	result := make(map[string]string, len(data))
+	for key, value := range data {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"govel/app/helper"
+)
+
+const (
+	// Key of the authenticated user id
+	AuthKey = "_auth"
+
+	// Key of the CSRF token
+	TokenKey = "_token"
+)
+
+type Session struct {
+	id          string
+	data        map[string]string
+	previousIds []string
+	destroyed   bool
+}
+
+func (session *Session) ID() string {
+	return session.id
+}
+
+func (session *Session) Get(key string) string {
+	return session.data[key]
+}
+
+func (session *Session) Has(key string) bool {
+	_, ok := session.data[key]
+	return ok
+}
+
+func (session *Session) Put(key string, value string) {
+	session.data[key] = value
+}
+
+func (session *Session) Forget(key string) {
+	delete(session.data, key)
+}
+
+// Get the CSRF token of the session, generate it if not exist
+func (session *Session) Token() string {
+	if !session.Has(TokenKey) {
+		session.RegenerateToken()
+	}
+	return session.Get(TokenKey)
+}
+
+func (session *Session) RegenerateToken() {
+	session.Put(TokenKey, helper.RandomString(40))
+}
+
+// Generate a new session id to avoid session fixation, the old session is
+// destroyed when the session saved
+func (session *Session) Regenerate() {
+	session.previousIds = append(session.previousIds, session.id)
+	session.id = helper.RandomString(40)
+}
+
+// Remove all data and generate a new session id and CSRF token
+func (session *Session) Invalidate() {
+	session.data = map[string]string{}
+	session.Regenerate()
+	session.RegenerateToken()
+}
+
+// Remove the session from the store and expire the cookie
+func (session *Session) Destroy() {
+	session.destroyed = true
+}
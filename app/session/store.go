@@ -0,0 +1,17 @@
+package session
+
+import "time"
+
+// Store persist the session data. The value is the cookie value sent by the
+// browser, server side stores use it as session id while the cookie store
+// keeps the whole payload in it.
+type Store interface {
+	// Read the session data, return nil if not found or expired
+	Read(value string) map[string]string
+
+	// Write the session data and return the new cookie value
+	Write(id string, data map[string]string, lifetime time.Duration) string
+
+	// Destroy the session data
+	Destroy(id string)
+}
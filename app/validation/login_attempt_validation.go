@@ -0,0 +1,20 @@
+package validation
+
+import (
+	"govel/app/exception"
+	"govel/app/model"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+func LoginAttemptListValidate(request model.GetLoginAttemptRequest) {
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.Token, validation.Required),
+		validation.Field(&request.Page, validation.Required, validation.Min(1)),
+		validation.Field(&request.Limit, validation.Required, validation.Min(1)),
+	)
+
+	if err != nil {
+		panic(exception.NewValidationError(err))
+	}
+}
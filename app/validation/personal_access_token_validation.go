@@ -0,0 +1,42 @@
+package validation
+
+import (
+	"govel/app/exception"
+	"govel/app/model"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+func PersonalAccessTokenCreateValidate(request model.CreatePersonalAccessTokenRequest) {
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.UserId, validation.Required),
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 255)),
+		validation.Field(&request.Abilities, validation.Required),
+		validation.Field(&request.ExpiresIn, validation.Min(0)),
+	)
+
+	if err != nil {
+		panic(exception.NewValidationError(err))
+	}
+}
+
+func PersonalAccessTokenListValidate(request model.GetPersonalAccessTokenRequest) {
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.UserId, validation.Required),
+	)
+
+	if err != nil {
+		panic(exception.NewValidationError(err))
+	}
+}
+
+func PersonalAccessTokenDeleteValidate(request model.DeletePersonalAccessTokenRequest) {
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.UserId, validation.Required),
+		validation.Field(&request.Id, validation.Required, validation.Min(1)),
+	)
+
+	if err != nil {
+		panic(exception.NewValidationError(err))
+	}
+}
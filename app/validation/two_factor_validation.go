@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"govel/app/exception"
+	"govel/app/model"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+func TwoFactorEnableValidate(request model.EnableTwoFactorRequest) {
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.Token, validation.Required),
+	)
+
+	if err != nil {
+		panic(exception.NewValidationError(err))
+	}
+}
+
+func TwoFactorConfirmValidate(request model.ConfirmTwoFactorRequest) {
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.Token, validation.Required),
+		validation.Field(&request.Code, validation.Required),
+	)
+
+	if err != nil {
+		panic(exception.NewValidationError(err))
+	}
+}
+
+func TwoFactorRecoveryCodesValidate(request model.RecoveryCodesTwoFactorRequest) {
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.Token, validation.Required),
+	)
+
+	if err != nil {
+		panic(exception.NewValidationError(err))
+	}
+}
+
+func TwoFactorChallengeValidate(request model.ChallengeTwoFactorRequest) {
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.Token, validation.Required),
+	)
+
+	if err != nil {
+		panic(exception.NewValidationError(err))
+	}
+
+	if request.Code == "" && request.RecoveryCode == "" {
+		panic(exception.ValidationError{
+			Message: "code: cannot be blank.",
+			Fields:  map[string]string{"code": "cannot be blank"},
+		})
+	}
+}
+
+func TwoFactorResetValidate(request model.ResetTwoFactorRequest) {
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.Token, validation.Required),
+		validation.Field(&request.Id, validation.Required, validation.Min(1)),
+	)
+
+	if err != nil {
+		panic(exception.NewValidationError(err))
+	}
+}
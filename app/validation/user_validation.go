@@ -13,9 +13,7 @@ func UserRefreshTokenValidate(request model.RefreshTokenUserRequest) {
 	)
 
 	if err != nil {
-		panic(exception.ValidationError{
-			Message: err.Error(),
-		})
+		panic(exception.NewValidationError(err))
 	}
 }
 
@@ -26,9 +24,7 @@ func UserLoginValidate(request model.LoginUserRequest) {
 	)
 
 	if err != nil {
-		panic(exception.ValidationError{
-			Message: err.Error(),
-		})
+		panic(exception.NewValidationError(err))
 	}
 }
 
@@ -41,14 +37,13 @@ func UserRegisterValidate(request model.RegisterUserRequest) {
 	)
 
 	if err != nil {
-		panic(exception.ValidationError{
-			Message: err.Error(),
-		})
+		panic(exception.NewValidationError(err))
 	}
 
 	if request.Password != request.Repassword {
 		panic(exception.ValidationError{
 			Message: "Password doesn't match.",
+			Fields:  map[string]string{"repassword": "doesn't match the password"},
 		})
 	}
 }
@@ -63,9 +58,7 @@ func UserUpdateValidate(request model.UpdateUserRequest) {
 	)
 
 	if err != nil {
-		panic(exception.ValidationError{
-			Message: err.Error(),
-		})
+		panic(exception.NewValidationError(err))
 	}
 }
 
@@ -76,9 +69,7 @@ func UserDeleteValidate(request model.DeleteUserRequest) {
 	)
 
 	if err != nil {
-		panic(exception.ValidationError{
-			Message: err.Error(),
-		})
+		panic(exception.NewValidationError(err))
 	}
 }
 
@@ -88,9 +79,7 @@ func UserShowValidate(request model.GetUserRequest) {
 	)
 
 	if err != nil {
-		panic(exception.ValidationError{
-			Message: err.Error(),
-		})
+		panic(exception.NewValidationError(err))
 	}
 }
 
@@ -101,9 +90,7 @@ func UserListhValidate(request model.GetUserRequest) {
 	)
 
 	if err != nil {
-		panic(exception.ValidationError{
-			Message: err.Error(),
-		})
+		panic(exception.NewValidationError(err))
 	}
 }
 
@@ -115,8 +102,6 @@ func UserSearchValidate(request model.GetUserRequest) {
 	)
 
 	if err != nil {
-		panic(exception.ValidationError{
-			Message: err.Error(),
-		})
+		panic(exception.NewValidationError(err))
 	}
 }
@@ -2,9 +2,19 @@ package bootstrap
 
 import (
 	"context"
+	"govel/app/connection"
+	"govel/app/container"
+	"govel/app/encryption"
+	"govel/app/exception"
+	"govel/app/hashing"
+	"govel/app/helper"
 	"govel/app/http/middleware"
+	"govel/app/lifecycle"
+	"govel/app/module"
+	"govel/app/provider"
+	"govel/app/schedule"
 	"govel/config"
-	"govel/route"
+	"log/slog"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,37 +22,142 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
-func Make(configuration config.Config) *fiber.App {
-	// Setup database
-	database := config.NewDatabase(configuration)
+// Make the app and its lifecycle, pass both to Serve. The overrides are
+// applied after the providers registered their bindings, e.g. to swap a
+// binding with a fake on tests.
+func Make(configuration config.Config, overrides ...func(c *container.Container)) (*fiber.App, *lifecycle.Lifecycle) {
+	app, hooks, c := build(configuration, overrides...)
+
+	// Report every missing binding at once, the connections are opened here
+	exception.PanicIfNeeded(c.Validate())
+
+	// Boot the registered hooks
+	err := hooks.Boot(context.Background())
+	exception.PanicIfNeeded(err)
+
+	return app, hooks
+}
+
+// Make the app with its routes only, e.g. to export the OpenAPI spec. The
+// bindings aren't resolved so no connection is opened, and the lifecycle
+// isn't booted so no worker or task runs. Shut the lifecycle down to close
+// the logs.
+func Routes(configuration config.Config) (*fiber.App, *lifecycle.Lifecycle) {
+	app, hooks, _ := build(configuration)
+	return app, hooks
+}
+
+// Make the app, the container and the lifecycle, the bindings are resolved
+// lazily and the hooks aren't booted
+func build(configuration config.Config, overrides ...func(c *container.Container)) (*fiber.App, *lifecycle.Lifecycle, *container.Container) {
+	// Validate every setting before anything is started
+	modules := Modules()
+	module.LoadConfig(configuration, modules)
+	settings, err := config.Load(configuration)
+	exception.PanicIfNeeded(err)
+	helper.ConfigureJWTKeys(settings.JWT.PrivateKeyFile, settings.JWT.PublicKeyFile)
+	encryption.SetDefault(config.NewEncrypter(configuration))
+	hashing.SetDefault(config.NewHasher(configuration))
+	exception.SetDebug(settings.App.Debug)
+	exception.SetRenderer(config.NewErrorRenderer(configuration))
+
+	// Hooks of this app only, every app made has its own
+	hooks := lifecycle.New()
+
+	// Setup Logger, the files are closed after every other shutdown hook
+	logger, logCloser := config.NewLogger(configuration)
+	slog.SetDefault(logger)
+	hooks.OnShutdown("log", func(ctx context.Context) error {
+		return logCloser.Close()
+	})
+
+	// Setup Tracer, the queued spans are exported before the logs are closed
+	tracerProvider, tracerShutdown := config.NewTracerProvider(configuration)
+	hooks.OnShutdown("trace", tracerShutdown)
 
 	// Setup Fiber
 	app := fiber.New(config.NewFiberConfig())
-	app.Use(recover.New())
+	registry := config.NewMetricsRegistry(configuration)
+	app.Use(middleware.RequestID)
+	if settings.Tracing.Exporter != "none" {
+		app.Use(middleware.Trace(tracerProvider))
+	}
+	if settings.Metrics.Enabled {
+		app.Use(middleware.Metrics(registry))
+	}
+	app.Use(middleware.LogRequest)
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace:  true,
+		StackTraceHandler: exception.SaveStack,
+	}))
 	app.Use(cors.New())
 
+	// Setup Container
+	c := container.New()
+	container.Instance(c, configuration)
+	container.Instance(c, settings)
+	container.Instance(c, app)
+	container.Instance(c, logger)
+	container.Instance(c, registry)
+	container.Instance(c, tracerProvider)
+	container.Instance(c, hooks)
+	providers := append(provider.Providers(), module.Providers(modules)...)
+	providers = append(providers, &provider.RouteServiceProvider{Registrars: routeRegistrars(modules)})
+	provider.Register(c, providers)
+	for _, override := range overrides {
+		override(c)
+	}
+
 	// Setup App Middleware
 	app.Use(middleware.AppMiddleware)
 
+	// Keep the queries to respond the failed SQL and to find the N+1 queries
+	// when debugging
+	if settings.App.Debug {
+		app.Use(middleware.InspectQueries(settings.Database.RepeatedQueryThreshold))
+	}
+
 	// Save databse object to fiber context
-	app.Use(func(c *fiber.Ctx) error {
-		timeoutContext, cancel := context.WithTimeout(context.Background(), time.Second)
+	app.Use(func(ctx *fiber.Ctx) error {
+		// Read from the primary after a write in the same request
+		ctx.SetUserContext(connection.WithSticky(ctx.UserContext()))
+
+		timeoutContext, cancel := context.WithTimeout(ctx.UserContext(), time.Second)
 		defer cancel()
 
-		c.Locals("DB", database.WithContext(timeoutContext))
-		return c.Next()
+		databases := container.Make[*connection.Manager](c)
+		ctx.Locals("DB", databases.Default().WithContext(timeoutContext))
+		return ctx.Next()
 	})
 
-	// Setup Routing
-	apiRoute := app.Group("/api", middleware.APIMiddleware)
-	route.APIRoute(apiRoute, database)
-	webRoute := app.Group("/", middleware.WebMiddleware)
-	route.WebRoute(webRoute, database)
+	// Boot the providers, the routes are registered here
+	err = provider.Boot(c, providers)
+	exception.PanicIfNeeded(err)
+
+	// Setup the scheduled tasks of the modules
+	tasks := schedule.New()
+	for _, module := range modules {
+		module.Schedule(tasks, c)
+	}
+	container.Instance(c, tasks)
+	if settings.App.Schedule {
+		hooks.OnBoot("schedule", func(ctx context.Context) error {
+			tasks.Start()
+			return nil
+		})
+		hooks.OnShutdown("schedule", tasks.Stop)
+	}
 
 	// 404 respond status if route path not found
-	app.Use(func(c *fiber.Ctx) error {
-		return c.SendStatus(404)
-	})
+	app.Use(middleware.NotFound)
+
+	return app, hooks, c
+}
 
-	return app
+func routeRegistrars(modules []module.Module) []provider.RouteRegistrar {
+	registrars := []provider.RouteRegistrar{}
+	for _, module := range modules {
+		registrars = append(registrars, module)
+	}
+	return registrars
 }
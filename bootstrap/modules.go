@@ -0,0 +1,13 @@
+package bootstrap
+
+import (
+	"govel/app/module"
+	"govel/app/module/auth"
+)
+
+// Modules of the app in order, add the module of your domain here
+func Modules() []module.Module {
+	return []module.Module{
+		auth.Module{},
+	}
+}
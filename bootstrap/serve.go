@@ -0,0 +1,62 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"govel/app/lifecycle"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Listen until SIGINT or SIGTERM, then stop accepting the connections, drain
+// the requests in flight and run the shutdown hooks of the app made with
+// Make within the timeout. The second signal exits at once.
+func Serve(app *fiber.App, hooks *lifecycle.Lifecycle, address string, timeout time.Duration) error {
+	// Registered last so it runs first, before the database is closed. The
+	// connections left are closed when the timeout passed.
+	hooks.OnShutdown("http", func(ctx context.Context) error {
+		if err := app.ShutdownWithContext(ctx); err != nil {
+			return fmt.Errorf("the requests were not drained within the timeout: %w", err)
+		}
+		return nil
+	})
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	listened := make(chan error, 1)
+	go func() {
+		listened <- app.Listen(address)
+	}()
+
+	select {
+	case err := <-listened:
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		hooks.Shutdown(ctx)
+		return err
+	case received := <-signals:
+		slog.Warn("Shutting down", slog.String("signal", received.String()), slog.Duration("timeout", timeout))
+	}
+
+	go func() {
+		received := <-signals
+		slog.Error("Exiting without the graceful shutdown", slog.String("signal", received.String()))
+		os.Exit(1)
+	}()
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := hooks.Shutdown(ctx); err != nil {
+		return err
+	}
+	slog.Info("Shutdown completed", slog.Duration("elapsed", time.Since(start)))
+	return nil
+}
@@ -1,29 +1,301 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"govel/app/encryption"
 	"govel/app/exception"
+	"govel/app/helper"
 
 	"github.com/joho/godotenv"
 )
 
+// Path of the config snapshot written by config:cache
+const CachePath = "bootstrap/cache/config.json"
+
 type Config interface {
+	// Get the value by dot notation key like "database.connections.mysql.host",
+	// the key without dot is read from the environment variables with the
+	// *_FILE and encrypted values resolved
 	Get(key string) string
+	GetInt(key string, fallback int) int
+	GetBool(key string, fallback bool) bool
+	GetDuration(key string, fallback time.Duration) time.Duration
+
+	// Get the raw value by dot notation key, a section is returned as Map
+	Value(key string) interface{}
+	Set(key string, value interface{})
+	All() Map
+
+	// Load the env files and resolve the config definitions, or load the
+	// config snapshot if cached
 	LoadEnv(filenames ...string)
+	Cached() bool
 }
 
-type configImpl struct{}
+type configImpl struct {
+	items  Map
+	cached bool
+}
 
 func New() Config {
-	return &configImpl{}
+	return &configImpl{
+		items: Map{},
+	}
 }
 
 func (config *configImpl) Get(key string) string {
-	return os.Getenv(key)
+	if !strings.Contains(key, ".") {
+		return env(key, "")
+	}
+	value := config.Value(key)
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// Get the integer value, the fallback is returned if empty or invalid
+func (config *configImpl) GetInt(key string, fallback int) int {
+	value, err := parseInt(config.Get(key), fallback)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Get the boolean value, the fallback is returned if empty or invalid
+func (config *configImpl) GetBool(key string, fallback bool) bool {
+	value, err := parseBool(config.Get(key), fallback)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Get the duration value like "30s" or "1h", the fallback is returned if
+// empty or invalid
+func (config *configImpl) GetDuration(key string, fallback time.Duration) time.Duration {
+	value, err := parseDuration(config.Get(key), fallback)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func (config *configImpl) Value(key string) interface{} {
+	var current interface{} = config.items
+	for _, segment := range strings.Split(key, ".") {
+		section, ok := current.(Map)
+		if !ok {
+			return nil
+		}
+		current = section[segment]
+	}
+	return current
 }
 
+func (config *configImpl) Set(key string, value interface{}) {
+	segments := strings.Split(key, ".")
+	section := config.items
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := section[segment].(Map)
+		if !ok {
+			next = Map{}
+			section[segment] = next
+		}
+		section = next
+	}
+	section[segments[len(segments)-1]] = value
+}
+
+func (config *configImpl) All() Map {
+	return config.items
+}
+
+func (config *configImpl) Cached() bool {
+	return config.cached
+}
+
+// Load the config snapshot if cached. Otherwise load the env files from the
+// project root in order .env, .env.{APP_ENV} and .env.local, the later file
+// overrides the earlier and the real environment variables override all of
+// them. Missing files are skipped. If the file names are given, only the
+// files are loaded and must exist.
 func (config *configImpl) LoadEnv(filenames ...string) {
-	err := godotenv.Load(filenames...)
+	if len(filenames) == 0 && config.loadCache() {
+		return
+	}
+
+	if len(filenames) > 0 {
+		err := godotenv.Load(filenames...)
+		exception.PanicIfNeeded(err)
+	} else {
+		loadEnvFiles()
+	}
+
+	// Resolve the config definitions with the environment variables
+	for _, definition := range definitions {
+		config.items[definition.name] = definition.resolve(env)
+	}
+}
+
+func (config *configImpl) loadCache() bool {
+	content, err := os.ReadFile(helper.BasePath(CachePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false
+	}
+	exception.PanicIfNeeded(err)
+
+	items := Map{}
+	err = json.Unmarshal(content, &items)
 	exception.PanicIfNeeded(err)
+	config.items = items
+	config.cached = true
+	return true
+}
+
+func loadEnvFiles() {
+	base := readEnvFile(helper.BasePath(".env"))
+	local := readEnvFile(helper.BasePath(".env.local"))
+
+	// Find the environment name to load the environment file
+	appEnv := os.Getenv("APP_ENV")
+	if appEnv == "" {
+		appEnv = local["APP_ENV"]
+	}
+	if appEnv == "" {
+		appEnv = base["APP_ENV"]
+	}
+	environment := map[string]string{}
+	if appEnv != "" {
+		environment = readEnvFile(helper.BasePath(".env." + appEnv))
+	}
+
+	// Merge the files and keep the real environment variables
+	merged := map[string]string{}
+	for _, values := range []map[string]string{base, environment, local} {
+		for key, value := range values {
+			merged[key] = value
+		}
+	}
+	for key, value := range merged {
+		if _, exists := os.LookupEnv(key); !exists {
+			os.Setenv(key, value)
+		}
+	}
+}
+
+// Read the env file, return empty values if the file doesn't exist
+func readEnvFile(filename string) map[string]string {
+	values, err := godotenv.Read(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}
+	}
+	exception.PanicIfNeeded(err)
+	return values
+}
+
+func parseInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(strings.TrimSpace(value))
+}
+
+func parseBool(value string, fallback bool) (bool, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.ParseBool(strings.TrimSpace(value))
+}
+
+func parseDuration(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return time.ParseDuration(strings.TrimSpace(value))
+}
+
+func init() {
+	Define("app", func(env Env) Map {
+		return Map{
+			"name":     env("APP_NAME", "Govel"),
+			"env":      env("APP_ENV", "production"),
+			"key":      env("APP_KEY", ""),
+			"debug":    env("APP_DEBUG", "false"),
+			"port":     env("APP_PORT", "8000"),
+			"timezone": env("APP_TIMEZONE", "UTC"),
+			"locale":   env("APP_LOCALE", "en"),
+			// Time to drain the requests and close the connections on shutdown
+			"shutdown_timeout": env("APP_SHUTDOWN_TIMEOUT", "30s"),
+			// Run the scheduled tasks of the modules, keep it on one replica only
+			"schedule": env("APP_SCHEDULE", "true"),
+			// Timeout of the outgoing calls of the shared HTTP client
+			"http_client_timeout": env("HTTP_CLIENT_TIMEOUT", "30s"),
+			// Format of the error responses, envelope or problem (RFC 7807)
+			"error_format": env("APP_ERROR_FORMAT", "envelope"),
+			// Base of the problem type URIs, "about:blank" if empty
+			"error_type_url": nullable(env("APP_ERROR_TYPE_URL", "")),
+		}
+	})
+}
+
+type AppConfig struct {
+	Name     string
+	Env      string
+	Key      string
+	Debug    bool
+	Port     int
+	Timezone string
+	Locale   string
+
+	ShutdownTimeout time.Duration
+	Schedule        bool
+
+	HTTPClientTimeout time.Duration
+	ErrorFormat       string
+	ErrorTypeURL      string
+}
+
+func LoadAppConfig(appConfig Config) (AppConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := AppConfig{
+		Name:     reader.String("app.name"),
+		Env:      reader.String("app.env"),
+		Key:      reader.String("app.key"),
+		Debug:    reader.Bool("app.debug"),
+		Port:     reader.Int("app.port"),
+		Timezone: reader.String("app.timezone"),
+		Locale:   reader.String("app.locale"),
+
+		ShutdownTimeout: reader.Duration("app.shutdown_timeout"),
+		Schedule:        reader.Bool("app.schedule"),
+
+		HTTPClientTimeout: reader.Duration("app.http_client_timeout"),
+		ErrorFormat:       reader.String("app.error_format"),
+		ErrorTypeURL:      reader.String("app.error_type_url"),
+	}
+
+	reader.Check(config.Port > 0 && config.Port <= 65535, "app.port must be between 1 and 65535")
+	reader.Check(config.ShutdownTimeout > 0, "app.shutdown_timeout must be greater than 0")
+	reader.Check(config.HTTPClientTimeout > 0, "app.http_client_timeout must be greater than 0")
+	reader.OneOf("app.error_format", config.ErrorFormat, "envelope", "problem")
+	// The key encrypts the user columns
+	if config.Key == "" {
+		reader.Fail("app.key is required, run ./govel key:generate")
+	} else if _, err := encryption.FromAppKey(config.Key); err != nil {
+		reader.Fail("app.key is invalid: " + err.Error())
+	}
+	if _, err := time.LoadLocation(config.Timezone); err != nil {
+		reader.Fail("app.timezone is not a valid timezone: " + config.Timezone)
+	}
+	return config, reader.Err()
 }
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"govel/app/exception"
+	"govel/app/service"
+	"time"
+)
+
+func init() {
+	Define("auth", func(env Env) Map {
+		return Map{
+			"login": Map{
+				"max_attempts":        env("LOGIN_MAX_ATTEMPTS", "5"),
+				"max_ip_attempts":     env("LOGIN_MAX_IP_ATTEMPTS", "20"),
+				"decay_minutes":       env("LOGIN_DECAY_MINUTES", "15"),
+				"lockout_seconds":     env("LOGIN_LOCKOUT_SECONDS", "60"),
+				"max_lockout_minutes": env("LOGIN_MAX_LOCKOUT_MINUTES", "60"),
+			},
+		}
+	})
+}
+
+type AuthConfig struct {
+	LoginMaxAttempts   int
+	LoginMaxIpAttempts int
+	// Failures are counted within the decay minutes
+	LoginDecayMinutes      int
+	LoginLockoutSeconds    int
+	LoginMaxLockoutMinutes int
+}
+
+func LoadAuthConfig(appConfig Config) (AuthConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := AuthConfig{
+		LoginMaxAttempts:       reader.Int("auth.login.max_attempts"),
+		LoginMaxIpAttempts:     reader.Int("auth.login.max_ip_attempts"),
+		LoginDecayMinutes:      reader.Int("auth.login.decay_minutes"),
+		LoginLockoutSeconds:    reader.Int("auth.login.lockout_seconds"),
+		LoginMaxLockoutMinutes: reader.Int("auth.login.max_lockout_minutes"),
+	}
+
+	reader.Check(config.LoginMaxAttempts >= 0, "auth.login.max_attempts must not be negative, 0 disables the limit")
+	reader.Check(config.LoginMaxIpAttempts >= 0, "auth.login.max_ip_attempts must not be negative, 0 disables the limit")
+	reader.Check(config.LoginDecayMinutes > 0, "auth.login.decay_minutes must be greater than 0")
+	reader.Check(config.LoginLockoutSeconds > 0, "auth.login.lockout_seconds must be greater than 0")
+	reader.Check(config.LoginMaxLockoutMinutes*60 >= config.LoginLockoutSeconds, "auth.login.max_lockout_minutes must not be less than auth.login.lockout_seconds")
+	return config, reader.Err()
+}
+
+func NewLoginThrottle(appConfig Config) service.LoginThrottle {
+	config, err := LoadAuthConfig(appConfig)
+	exception.PanicIfNeeded(err)
+
+	return service.LoginThrottle{
+		MaxAttempts:   config.LoginMaxAttempts,
+		MaxIpAttempts: config.LoginMaxIpAttempts,
+		Decay:         time.Duration(config.LoginDecayMinutes) * time.Minute,
+		Lockout:       time.Duration(config.LoginLockoutSeconds) * time.Second,
+		MaxLockout:    time.Duration(config.LoginMaxLockoutMinutes) * time.Minute,
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+func init() {
+	Define("cache", func(env Env) Map {
+		return Map{
+			"default": env("CACHE_DRIVER", "memory"),
+			"stores": Map{
+				"redis": Map{
+					"host":     env("REDIS_HOST", "127.0.0.1"),
+					"password": nullable(env("REDIS_PASSWORD", "")),
+					"port":     env("REDIS_PORT", "6379"),
+					"database": env("REDIS_CACHE_DB", "0"),
+				},
+			},
+		}
+	})
+}
+
+type CacheConfig struct {
+	Driver        string
+	RedisHost     string
+	RedisPassword string
+	RedisPort     int
+	RedisDatabase int
+}
+
+func LoadCacheConfig(appConfig Config) (CacheConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := CacheConfig{
+		Driver:        reader.String("cache.default"),
+		RedisHost:     reader.String("cache.stores.redis.host"),
+		RedisPassword: reader.String("cache.stores.redis.password"),
+		RedisPort:     reader.Int("cache.stores.redis.port"),
+		RedisDatabase: reader.Int("cache.stores.redis.database"),
+	}
+
+	reader.OneOf("cache.default", config.Driver, "memory", "redis")
+	if config.Driver == "redis" {
+		reader.Check(config.RedisHost != "", "cache.stores.redis.host is required by the redis cache driver")
+		reader.Check(config.RedisPort > 0 && config.RedisPort <= 65535, "cache.stores.redis.port must be between 1 and 65535")
+		reader.Check(config.RedisDatabase >= 0, "cache.stores.redis.database must not be negative")
+	}
+	return config, reader.Err()
+}
@@ -1,41 +1,380 @@
 package config
 
 import (
+	"database/sql"
+	"govel/app/connection"
 	"govel/app/exception"
+	"govel/app/helper"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/driver/sqlserver"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
-func NewDatabase(appConfig Config) *gorm.DB {
-	var database *gorm.DB
-	var err error
-	if appConfig.Get("DB_CONNECTION") == "mysql" {
-		dsn := appConfig.Get("DB_USERNAME") + ":" + appConfig.Get("DB_PASSWORD") + "@tcp(" + appConfig.Get("DB_HOST") + ":" + appConfig.Get("DB_PORT") + ")/" + appConfig.Get("DB_DATABASE") + "?charset=utf8mb4&parseTime=True&loc=" + strings.ReplaceAll(appConfig.Get("DB_TIMEZONE"), "/", "%2F")
-		database, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
-	} else if appConfig.Get("DB_CONNECTION") == "postgres" {
-		dsn := "host=" + appConfig.Get("DB_HOST") + " user=" + appConfig.Get("DB_USERNAME") + " password=" + appConfig.Get("DB_PASSWORD") + " dbname=" + appConfig.Get("DB_DATABASE") + " port=" + appConfig.Get("DB_PORT") + " sslmode=disable TimeZone=" + appConfig.Get("DB_TIMEZONE")
-		database, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	} else if appConfig.Get("DB_CONNECTION") == "sqlite" {
-		database, err = gorm.Open(sqlite.Open(appConfig.Get("DB_DATABASE")), &gorm.Config{})
-	} else if appConfig.Get("DB_CONNECTION") == "sqlserver" {
-		dsn := "sqlserver://" + appConfig.Get("DB_USERNAME") + ":" + appConfig.Get("DB_PASSWORD") + "@" + appConfig.Get("DB_HOST") + ":" + appConfig.Get("DB_PORT") + "?database=" + appConfig.Get("DB_DATABASE")
-		database, err = gorm.Open(sqlserver.Open(dsn), &gorm.Config{})
+func init() {
+	Define("database", func(env Env) Map {
+		connection := func(driver string, port string, sslmode string) Map {
+			return Map{
+				"driver":             driver,
+				"url":                env("DATABASE_URL", ""),
+				"host":               env("DB_HOST", "127.0.0.1"),
+				"port":               env("DB_PORT", port),
+				"database":           env("DB_DATABASE", ""),
+				"username":           env("DB_USERNAME", ""),
+				"password":           env("DB_PASSWORD", ""),
+				"timezone":           env("DB_TIMEZONE", "UTC"),
+				"charset":            env("DB_CHARSET", ""),
+				"sslmode":            env("DB_SSLMODE", sslmode),
+				"sslrootcert":        env("DB_SSLROOTCERT", ""),
+				"sslcert":            env("DB_SSLCERT", ""),
+				"sslkey":             env("DB_SSLKEY", ""),
+				"options":            env("DB_OPTIONS", ""),
+				"max_idle_conns":     env("DB_MAX_IDLE_CONNS", "10"),
+				"max_open_conns":     env("DB_MAX_OPEN_CONNS", "100"),
+				"conn_max_lifetime":  env("DB_CONN_MAX_LIFETIME", "1h"),
+				"conn_max_idle_time": env("DB_CONN_MAX_IDLE_TIME", "0"),
+				"connect_timeout":    env("DB_CONNECT_TIMEOUT", "30s"),
+				"read": Map{
+					// Comma separated hosts like "10.0.0.2,10.0.0.3:3307"
+					"hosts": env("DB_READ_HOSTS", ""),
+				},
+				"sticky": env("DB_STICKY", "true"),
+			}
+		}
+
+		// Add the named connections here and get them with
+		// databases.Connection("analytics"), e.g.
+		// "analytics": Map{"driver": "postgres", "host": env("ANALYTICS_DB_HOST", ""), ...}
+		return Map{
+			"default": env("DB_CONNECTION", "mysql"),
+			"health": Map{
+				"interval": env("DB_HEALTH_INTERVAL", "15s"),
+				"timeout":  env("DB_HEALTH_TIMEOUT", "2s"),
+			},
+			"queries": Map{
+				// Log the slower queries, 0 disables it
+				"slow_threshold": env("DB_SLOW_QUERY_THRESHOLD", "500ms"),
+				// Warn of the query run this many times by a request when
+				// debugging, likely an N+1 query. 0 disables it
+				"repeated_threshold": env("DB_REPEATED_QUERY_THRESHOLD", "5"),
+			},
+			"connections": Map{
+				"mysql":    connection("mysql", "3306", "prefer"),
+				"postgres": connection("postgres", "5432", "disable"),
+				"sqlite": Map{
+					"driver":             "sqlite",
+					"database":           env("DB_DATABASE", ""),
+					"options":            env("DB_OPTIONS", ""),
+					"max_idle_conns":     env("DB_MAX_IDLE_CONNS", "10"),
+					"max_open_conns":     env("DB_MAX_OPEN_CONNS", "100"),
+					"conn_max_lifetime":  env("DB_CONN_MAX_LIFETIME", "1h"),
+					"conn_max_idle_time": env("DB_CONN_MAX_IDLE_TIME", "0"),
+					"connect_timeout":    env("DB_CONNECT_TIMEOUT", "30s"),
+				},
+				"sqlserver": connection("sqlserver", "1433", "prefer"),
+			},
+		}
+	})
+}
+
+type DatabaseConfig struct {
+	Connection string
+	Driver     string
+	// Raw DSN, the connection settings below are ignored if set
+	URL      string
+	Host     string
+	Port     int
+	Database string
+	Username string
+	Password string
+	Timezone string
+	Charset  string
+	// One of disable, prefer, require, verify-ca or verify-full
+	SSLMode     string
+	SSLRootCert string
+	SSLCert     string
+	SSLKey      string
+	// Extra DSN parameters like "a=1&b=2"
+	Options string
+
+	// Hosts of the read replicas, the other settings are same as the primary
+	ReadHosts []string
+	// Read from the primary after a write in the same request
+	Sticky bool
+
+	MaxIdleConns    int
+	MaxOpenConns    int
+	ConnMaxLifetime time.Duration
+	// Zero keeps the idle connections forever
+	ConnMaxIdleTime time.Duration
+	// Retry to connect on boot until the timeout, zero tries once
+	ConnectTimeout time.Duration
+
+	// Ping the opened connections every interval
+	HealthInterval time.Duration
+	HealthTimeout  time.Duration
+
+	// Log the queries slower than the threshold, zero disables it
+	SlowQueryThreshold time.Duration
+	// Warn of the query run this many times by a request in debug mode, zero
+	// disables it
+	RepeatedQueryThreshold int
+}
+
+// Load the settings of the default connection
+func LoadDatabaseConfig(appConfig Config) (DatabaseConfig, error) {
+	return LoadConnectionConfig(appConfig, appConfig.Get("database.default"))
+}
+
+// Load the settings of the named connection under database.connections
+func LoadConnectionConfig(appConfig Config, name string) (DatabaseConfig, error) {
+	reader := newConfigReader(appConfig)
+	prefix := "database.connections." + name
+	if _, ok := appConfig.Value(prefix).(Map); name == "" || !ok {
+		reader.Fail(prefix + " is not defined")
+		return DatabaseConfig{Connection: name}, reader.Err()
+	}
+
+	config := loadConnection(reader, prefix)
+	config.Connection = name
+	config.HealthInterval = reader.Duration("database.health.interval")
+	config.HealthTimeout = reader.Duration("database.health.timeout")
+	reader.Check(config.HealthInterval > 0, "database.health.interval must be greater than 0")
+	reader.Check(config.HealthTimeout > 0, "database.health.timeout must be greater than 0")
+	config.SlowQueryThreshold = reader.Duration("database.queries.slow_threshold")
+	config.RepeatedQueryThreshold = reader.Int("database.queries.repeated_threshold")
+	reader.Check(config.SlowQueryThreshold >= 0, "database.queries.slow_threshold must not be negative")
+	reader.Check(config.RepeatedQueryThreshold >= 0, "database.queries.repeated_threshold must not be negative")
+	return config, reader.Err()
+}
+
+func loadConnection(reader *configReader, prefix string) DatabaseConfig {
+	config := DatabaseConfig{
+		Driver:          reader.String(prefix + ".driver"),
+		URL:             reader.String(prefix + ".url"),
+		Database:        reader.String(prefix + ".database"),
+		Options:         reader.String(prefix + ".options"),
+		MaxIdleConns:    reader.Int(prefix + ".max_idle_conns"),
+		MaxOpenConns:    reader.Int(prefix + ".max_open_conns"),
+		ConnMaxLifetime: reader.Duration(prefix + ".conn_max_lifetime"),
+		ConnMaxIdleTime: reader.Duration(prefix + ".conn_max_idle_time"),
+		ConnectTimeout:  reader.Duration(prefix + ".connect_timeout"),
 	}
+
+	reader.OneOf(prefix+".driver", config.Driver, "mysql", "postgres", "sqlite", "sqlserver")
+	reader.Check(config.MaxIdleConns >= 0, prefix+".max_idle_conns must not be negative")
+	reader.Check(config.MaxOpenConns >= 0, prefix+".max_open_conns must not be negative, 0 is unlimited")
+	reader.Check(config.ConnMaxLifetime >= 0, prefix+".conn_max_lifetime must not be negative")
+	reader.Check(config.ConnMaxIdleTime >= 0, prefix+".conn_max_idle_time must not be negative")
+	reader.Check(config.ConnectTimeout >= 0, prefix+".connect_timeout must not be negative")
+	if _, err := url.ParseQuery(config.Options); err != nil {
+		reader.Fail(prefix + ".options must be like a=1&b=2: " + err.Error())
+	}
+	if config.URL != "" {
+		reader.Check(reader.String(prefix+".read.hosts") == "", prefix+".read.hosts can't be used with "+prefix+".url")
+		return config
+	}
+
+	reader.Check(config.Database != "", prefix+".database is required")
+	if config.Driver == "sqlite" {
+		return config
+	}
+
+	config.Host = reader.String(prefix + ".host")
+	config.Port = reader.Int(prefix + ".port")
+	config.Username = reader.String(prefix + ".username")
+	config.Password = reader.String(prefix + ".password")
+	config.Timezone = reader.String(prefix + ".timezone")
+	config.Charset = reader.String(prefix + ".charset")
+	config.SSLMode = reader.String(prefix + ".sslmode")
+	config.SSLRootCert = reader.String(prefix + ".sslrootcert")
+	config.SSLCert = reader.String(prefix + ".sslcert")
+	config.SSLKey = reader.String(prefix + ".sslkey")
+	config.Sticky = reader.Bool(prefix + ".sticky")
+	for _, host := range strings.Split(reader.String(prefix+".read.hosts"), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			config.ReadHosts = append(config.ReadHosts, host)
+		}
+	}
+
+	reader.Check(config.Host != "", prefix+".host is required")
+	reader.Check(config.Port > 0 && config.Port <= 65535, prefix+".port must be between 1 and 65535")
+	reader.OneOf(prefix+".sslmode", config.SSLMode, "disable", "prefer", "require", "verify-ca", "verify-full")
+	reader.Check((config.SSLCert == "") == (config.SSLKey == ""), prefix+".sslcert and "+prefix+".sslkey must be set together")
+	for _, key := range []string{"sslrootcert", "sslcert", "sslkey"} {
+		if filename := reader.String(prefix + "." + key); filename != "" {
+			if _, err := os.Stat(helper.BasePath(filename)); err != nil {
+				reader.Fail(prefix + "." + key + " is not readable: " + err.Error())
+			}
+		}
+	}
+	return config
+}
+
+// Open the default connection
+func NewDatabase(appConfig Config) *gorm.DB {
+	config, err := LoadDatabaseConfig(appConfig)
 	exception.PanicIfNeeded(err)
+	return OpenDatabase(config)
+}
 
-	sqlDB, err := database.DB()
+// Make the manager of the named connections, the connections are opened on
+// first use, record the query durations and the pool stats to the
+// registerer and trace the queries with the provider
+func NewDatabaseManager(appConfig Config, registerer prometheus.Registerer, provider trace.TracerProvider) *connection.Manager {
+	return connection.NewManager(appConfig.Get("database.default"), func(name string) *gorm.DB {
+		config, err := LoadConnectionConfig(appConfig, name)
+		exception.PanicIfNeeded(err)
+		database := OpenDatabase(config)
+		exception.PanicIfNeeded(database.Use(&connection.Metrics{Connection: config.Connection, Registerer: registerer}))
+		// The SQL has the placeholders, never the values
+		exception.PanicIfNeeded(database.Use(tracing.NewPlugin(
+			tracing.WithTracerProvider(provider),
+			tracing.WithDBName(config.Connection),
+			tracing.WithoutQueryVariables(),
+			tracing.WithoutMetrics(),
+		)))
+		return database
+	})
+}
+
+// Open the connection with its read replicas, retry until the connect
+// timeout if the database is not reachable yet
+func OpenDatabase(config DatabaseConfig) *gorm.DB {
+	database, err := ConnectDatabase(config, config.ConnectTimeout)
 	exception.PanicIfNeeded(err)
+	return database
+}
+
+// Connect with retries until the timeout, zero timeout tries once
+func ConnectDatabase(config DatabaseConfig, timeout time.Duration) (database *gorm.DB, err error) {
+	err = connection.Retry(timeout, func(attempt int) error {
+		database, err = connect(config)
+		if err != nil {
+			slog.Warn("Database connection is not reachable",
+				slog.String("connection", config.Connection),
+				slog.Int("attempt", attempt),
+				slog.String("error", err.Error()),
+			)
+		}
+		return err
+	})
+	return database, err
+}
+
+func connect(config DatabaseConfig) (database *gorm.DB, err error) {
+	database, err = openPool(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close the pools opened so far if the connection can't be set up
+	replicas := []*sql.DB{}
+	defer func() {
+		if err != nil {
+			for _, replica := range replicas {
+				replica.Close()
+			}
+			closePool(database)
+			database = nil
+		}
+	}()
+
+	// Log the queries of the contexts started with connection.WithQueryLog
+	if err := database.Use(&connection.QueryLogger{}); err != nil {
+		return nil, err
+	}
+	if config.SlowQueryThreshold > 0 {
+		if err := database.Use(&connection.SlowQueryLogger{Connection: config.Connection, Threshold: config.SlowQueryThreshold}); err != nil {
+			return nil, err
+		}
+	}
+	if len(config.ReadHosts) == 0 {
+		return database, nil
+	}
+
+	for _, host := range config.ReadHosts {
+		replica := config
+		replica.Host = host
+		if name, port, err := net.SplitHostPort(host); err == nil {
+			replica.Host = name
+			replica.Port, _ = strconv.Atoi(port)
+		}
+		replicaDB, err := openPool(replica)
+		if err != nil {
+			return nil, err
+		}
+		sqlDB, _ := replicaDB.DB()
+		replicas = append(replicas, sqlDB)
+	}
+
+	if err := database.Use(connection.NewResolver(replicas, config.Sticky)); err != nil {
+		return nil, err
+	}
+	return database, nil
+}
+
+func openPool(config DatabaseConfig) (*gorm.DB, error) {
+	dsn, err := config.DSN()
+	if err != nil {
+		return nil, err
+	}
+
+	var dialector gorm.Dialector
+	switch config.Driver {
+	case "mysql":
+		dialector = mysql.Open(dsn)
+	case "postgres":
+		dialector = postgres.Open(dsn)
+	case "sqlite":
+		dialector = sqlite.Open(dsn)
+	case "sqlserver":
+		dialector = sqlserver.Open(dsn)
+	}
+
+	// Ping on open to fail if not reachable. Open silently, the error is
+	// logged with the attempt by ConnectDatabase.
+	database, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		// The pool is opened even if the ping failed
+		closePool(database)
+		return nil, err
+	}
+	database.Logger = logger.Default
+
+	sqlDB, err := database.DB()
+	if err != nil {
+		return nil, err
+	}
 
 	// Setup connection pool
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
 
-	return database
+	return database, nil
+}
+
+func closePool(database *gorm.DB) {
+	if database == nil {
+		return
+	}
+	// The pool is nil if the dialector failed to open it
+	if sqlDB, err := database.DB(); err == nil && sqlDB != nil {
+		sqlDB.Close()
+	}
 }
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"govel/app/helper"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Build the DSN of the driver, the URL is used as is if set
+func (config DatabaseConfig) DSN() (string, error) {
+	if config.URL != "" {
+		return config.URL, nil
+	}
+
+	options, err := url.ParseQuery(config.Options)
+	if err != nil {
+		return "", err
+	}
+	port := strconv.Itoa(config.Port)
+
+	switch config.Driver {
+	case "mysql":
+		tlsName, err := config.mysqlTLS()
+		if err != nil {
+			return "", err
+		}
+		charset := config.Charset
+		if charset == "" {
+			charset = "utf8mb4"
+		}
+		params := url.Values{
+			"charset":   {charset},
+			"parseTime": {"True"},
+			"loc":       {config.Timezone},
+			"tls":       {tlsName},
+		}
+		return config.Username + ":" + config.Password + "@tcp(" + config.Host + ":" + port + ")/" + config.Database + "?" + merge(params, options).Encode(), nil
+
+	case "postgres":
+		params := url.Values{
+			"host":     {config.Host},
+			"port":     {port},
+			"user":     {config.Username},
+			"password": {config.Password},
+			"dbname":   {config.Database},
+			"sslmode":  {config.SSLMode},
+			"TimeZone": {config.Timezone},
+		}
+		if config.Charset != "" {
+			params.Set("client_encoding", config.Charset)
+		}
+		for key, file := range map[string]string{"sslrootcert": config.SSLRootCert, "sslcert": config.SSLCert, "sslkey": config.SSLKey} {
+			if file != "" {
+				params.Set(key, helper.BasePath(file))
+			}
+		}
+		return postgresDSN(merge(params, options)), nil
+
+	case "sqlite":
+		if len(options) == 0 {
+			return config.Database, nil
+		}
+		return config.Database + "?" + options.Encode(), nil
+
+	case "sqlserver":
+		params := url.Values{"database": {config.Database}}
+		switch config.SSLMode {
+		case "disable":
+			params.Set("encrypt", "disable")
+		case "prefer":
+			params.Set("encrypt", "false")
+		case "require":
+			params.Set("encrypt", "true")
+			params.Set("TrustServerCertificate", "true")
+		case "verify-ca", "verify-full":
+			params.Set("encrypt", "true")
+			if config.SSLRootCert != "" {
+				params.Set("certificate", helper.BasePath(config.SSLRootCert))
+			}
+		}
+		dsn := url.URL{
+			Scheme:   "sqlserver",
+			User:     url.UserPassword(config.Username, config.Password),
+			Host:     config.Host + ":" + port,
+			RawQuery: merge(params, options).Encode(),
+		}
+		return dsn.String(), nil
+	}
+	return "", errors.New("Unsupported database driver " + config.Driver)
+}
+
+// The DSN with the password masked
+func (config DatabaseConfig) RedactedDSN() string {
+	if config.URL != "" {
+		return redactDSN(config.URL)
+	}
+	if config.Password != "" {
+		config.Password = "xxxxxxxx"
+	}
+	dsn, err := config.DSN()
+	if err != nil {
+		return err.Error()
+	}
+	return strings.ReplaceAll(dsn, "xxxxxxxx", "********")
+}
+
+var (
+	// user:password@host
+	dsnUserPassword = regexp.MustCompile(`^((?:[a-z][a-z0-9+.-]*://)?[^:/@]*:)[^@]*@`)
+	// password=secret
+	dsnPasswordParam = regexp.MustCompile(`(?i)(password=)[^\s&;]*`)
+)
+
+func redactDSN(dsn string) string {
+	dsn = dsnUserPassword.ReplaceAllString(dsn, "${1}********@")
+	return dsnPasswordParam.ReplaceAllString(dsn, "${1}********")
+}
+
+// Register the TLS config of the sslmode for the mysql driver
+func (config DatabaseConfig) mysqlTLS() (string, error) {
+	switch config.SSLMode {
+	case "disable":
+		return "false", nil
+	case "prefer":
+		return "preferred", nil
+	case "require":
+		if config.SSLCert == "" {
+			return "skip-verify", nil
+		}
+	}
+
+	tlsConfig := &tls.Config{ServerName: config.Host}
+	if config.SSLRootCert != "" {
+		pem, err := os.ReadFile(helper.BasePath(config.SSLRootCert))
+		if err != nil {
+			return "", err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return "", errors.New("No certificate found in " + config.SSLRootCert)
+		}
+	}
+	if config.SSLCert != "" {
+		certificate, err := tls.LoadX509KeyPair(helper.BasePath(config.SSLCert), helper.BasePath(config.SSLKey))
+		if err != nil {
+			return "", err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	switch config.SSLMode {
+	case "require":
+		tlsConfig.InsecureSkipVerify = true
+	case "verify-ca":
+		// Verify the chain but not the host name
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyChain(tlsConfig.RootCAs)
+	}
+
+	name := "govel-" + config.Connection + "-" + config.Host
+	if err := mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func verifyChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		certificates := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			certificate, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certificates[i] = certificate
+		}
+		if len(certificates) == 0 {
+			return errors.New("No server certificate")
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, certificate := range certificates[1:] {
+			intermediates.AddCert(certificate)
+		}
+		_, err := certificates[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+		return err
+	}
+}
+
+// Add the options to the params, the options override the params
+func merge(params url.Values, options url.Values) url.Values {
+	for key, values := range options {
+		params[key] = values
+	}
+	return params
+}
+
+// Format the params as "key=value key=value" of libpq
+func postgresDSN(params url.Values) string {
+	parts := []string{}
+	for key := range params {
+		value := params.Get(key)
+		if value == "" || strings.ContainsAny(value, ` '\`) {
+			value = "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+		}
+		parts = append(parts, key+"="+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
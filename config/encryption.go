@@ -0,0 +1,18 @@
+package config
+
+import (
+	"govel/app/encryption"
+	"govel/app/exception"
+)
+
+// Make the encrypter from app.key, nil if the key is not set
+func NewEncrypter(appConfig Config) encryption.Encrypter {
+	key := appConfig.Get("app.key")
+	if key == "" {
+		return nil
+	}
+
+	encrypter, err := encryption.FromAppKey(key)
+	exception.PanicIfNeeded(err)
+	return encrypter
+}
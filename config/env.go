@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// All invalid settings found while loading the config
+type ValidationErrors []string
+
+func (errors ValidationErrors) Error() string {
+	return "Invalid configuration:\n- " + strings.Join(errors, "\n- ")
+}
+
+// Typed settings of every domain loaded from the config
+type Settings struct {
+	App      AppConfig
+	Database DatabaseConfig
+	Mail     MailConfig
+	Cache    CacheConfig
+	JWT      JWTConfig
+	Session  SessionConfig
+	Hashing  HashingConfig
+	Auth     AuthConfig
+	Queue    QueueConfig
+	Health   HealthConfig
+	Logging  LoggingConfig
+	Metrics  MetricsConfig
+	Tracing  TracingConfig
+	OpenAPI  OpenAPIConfig
+}
+
+// Load and validate the settings of every domain, the error contains all of
+// the invalid settings at once
+func Load(appConfig Config) (settings Settings, err error) {
+	errors := ValidationErrors{}
+	collect := func(err error) {
+		if err != nil {
+			errors = append(errors, err.(ValidationErrors)...)
+		}
+	}
+
+	settings.App, err = LoadAppConfig(appConfig)
+	collect(err)
+	settings.Database, err = LoadDatabaseConfig(appConfig)
+	collect(err)
+	settings.Mail, err = LoadMailConfig(appConfig)
+	collect(err)
+	settings.Cache, err = LoadCacheConfig(appConfig)
+	collect(err)
+	settings.JWT, err = LoadJWTConfig(appConfig)
+	collect(err)
+	settings.Session, err = LoadSessionConfig(appConfig)
+	collect(err)
+	settings.Hashing, err = LoadHashingConfig(appConfig)
+	collect(err)
+	settings.Auth, err = LoadAuthConfig(appConfig)
+	collect(err)
+	settings.Queue, err = LoadQueueConfig(appConfig)
+	collect(err)
+	settings.Health, err = LoadHealthConfig(appConfig)
+	collect(err)
+	settings.Logging, err = LoadLoggingConfig(appConfig)
+	collect(err)
+	settings.Metrics, err = LoadMetricsConfig(appConfig)
+	collect(err)
+	settings.Tracing, err = LoadTracingConfig(appConfig)
+	collect(err)
+	settings.OpenAPI, err = LoadOpenAPIConfig(appConfig)
+	collect(err)
+
+	if len(errors) > 0 {
+		return settings, errors
+	}
+	return settings, nil
+}
+
+// Compare the keys of the env file with the example file
+func CheckEnv(envFile string, exampleFile string) (missing []string, extra []string, err error) {
+	env, err := godotenv.Read(envFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	example, err := godotenv.Read(exampleFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for key := range example {
+		if _, ok := env[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	for key := range env {
+		if _, ok := example[key]; !ok {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	return missing, extra, nil
+}
+
+// Read the config values by type and collect the invalid values
+type configReader struct {
+	config Config
+	errors ValidationErrors
+}
+
+func newConfigReader(appConfig Config) *configReader {
+	return &configReader{config: appConfig}
+}
+
+func (reader *configReader) String(key string) string {
+	return reader.config.Get(key)
+}
+
+func (reader *configReader) Int(key string) int {
+	value, err := parseInt(reader.config.Get(key), 0)
+	if err != nil {
+		reader.Fail(fmt.Sprintf("%s must be an integer, got %q", key, reader.config.Get(key)))
+	}
+	return value
+}
+
+func (reader *configReader) Bool(key string) bool {
+	value, err := parseBool(reader.config.Get(key), false)
+	if err != nil {
+		reader.Fail(fmt.Sprintf("%s must be a boolean, got %q", key, reader.config.Get(key)))
+	}
+	return value
+}
+
+func (reader *configReader) Float(key string) float64 {
+	value, err := strconv.ParseFloat(reader.config.Get(key), 64)
+	if err != nil {
+		reader.Fail(fmt.Sprintf("%s must be a number, got %q", key, reader.config.Get(key)))
+	}
+	return value
+}
+
+func (reader *configReader) Duration(key string) time.Duration {
+	value, err := parseDuration(reader.config.Get(key), 0)
+	if err != nil {
+		reader.Fail(fmt.Sprintf("%s must be a duration like 30s or 5m, got %q", key, reader.config.Get(key)))
+	}
+	return value
+}
+
+// Add the message if the condition is false
+func (reader *configReader) Check(ok bool, message string) {
+	if !ok {
+		reader.Fail(message)
+	}
+}
+
+// Check the value is one of the options
+func (reader *configReader) OneOf(key string, value string, options ...string) {
+	for _, option := range options {
+		if value == option {
+			return
+		}
+	}
+	reader.Fail(fmt.Sprintf("%s must be one of %s, got %q", key, strings.Join(options, ", "), value))
+}
+
+func (reader *configReader) Fail(message string) {
+	reader.errors = append(reader.errors, message)
+}
+
+func (reader *configReader) Err() error {
+	if len(reader.errors) == 0 {
+		return nil
+	}
+	return reader.errors
+}
@@ -11,3 +11,17 @@ func NewFiberConfig() fiber.Config {
 		ErrorHandler: exception.ErrorHandler,
 	}
 }
+
+// Make the renderer of APP_ERROR_FORMAT, the clients accepting
+// application/problem+json get the problem format anyway
+func NewErrorRenderer(appConfig Config) exception.Renderer {
+	config, err := LoadAppConfig(appConfig)
+	exception.PanicIfNeeded(err)
+
+	problem := exception.ProblemRenderer{TypeBaseURL: config.ErrorTypeURL}
+	var format exception.Renderer = exception.EnvelopeRenderer{}
+	if config.ErrorFormat == "problem" {
+		format = problem
+	}
+	return exception.NegotiatingRenderer{Default: format, Problem: problem}
+}
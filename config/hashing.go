@@ -0,0 +1,64 @@
+package config
+
+import (
+	"govel/app/exception"
+	"govel/app/hashing"
+)
+
+func init() {
+	Define("hashing", func(env Env) Map {
+		return Map{
+			"driver": env("HASH_DRIVER", "bcrypt"),
+			"bcrypt": Map{
+				"rounds": env("BCRYPT_ROUNDS", "10"),
+			},
+			"argon": Map{
+				"memory":  env("ARGON_MEMORY", "65536"),
+				"time":    env("ARGON_TIME", "4"),
+				"threads": env("ARGON_THREADS", "1"),
+			},
+		}
+	})
+}
+
+type HashingConfig struct {
+	Driver       string
+	BcryptRounds int
+	// Argon memory in KiB
+	ArgonMemory  int
+	ArgonTime    int
+	ArgonThreads int
+}
+
+func LoadHashingConfig(appConfig Config) (HashingConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := HashingConfig{
+		Driver:       reader.String("hashing.driver"),
+		BcryptRounds: reader.Int("hashing.bcrypt.rounds"),
+		ArgonMemory:  reader.Int("hashing.argon.memory"),
+		ArgonTime:    reader.Int("hashing.argon.time"),
+		ArgonThreads: reader.Int("hashing.argon.threads"),
+	}
+
+	reader.OneOf("hashing.driver", config.Driver, "bcrypt", "argon2id")
+	reader.Check(config.BcryptRounds >= 4 && config.BcryptRounds <= 31, "hashing.bcrypt.rounds must be between 4 and 31")
+	reader.Check(config.ArgonMemory >= 8, "hashing.argon.memory must be at least 8 KiB")
+	reader.Check(config.ArgonTime >= 1, "hashing.argon.time must be at least 1")
+	reader.Check(config.ArgonThreads >= 1 && config.ArgonThreads <= 255, "hashing.argon.threads must be between 1 and 255")
+	return config, reader.Err()
+}
+
+func NewHasher(appConfig Config) hashing.Hasher {
+	config, err := LoadHashingConfig(appConfig)
+	exception.PanicIfNeeded(err)
+
+	return hashing.New(
+		config.Driver,
+		hashing.NewBcryptHasher(config.BcryptRounds),
+		hashing.NewArgon2idHasher(hashing.Argon2idOptions{
+			Memory:  uint32(config.ArgonMemory),
+			Time:    uint32(config.ArgonTime),
+			Threads: uint8(config.ArgonThreads),
+		}),
+	)
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"context"
+	"govel/app/connection"
+	"govel/app/exception"
+	"govel/app/health"
+	"govel/app/helper"
+	"govel/app/model"
+	"net"
+	"strconv"
+	"time"
+)
+
+func init() {
+	Define("health", func(env Env) Map {
+		return Map{
+			"timeout": env("HEALTH_TIMEOUT", "2s"),
+			// Bearer token of the requests seeing the errors and the details
+			// of the checks, only the debug mode shows them if empty
+			"token": nullable(env("HEALTH_TOKEN", "")),
+			"disk": Map{
+				"path":        env("HEALTH_DISK_PATH", "storage"),
+				"min_free_mb": env("HEALTH_DISK_MIN_FREE_MB", "100"),
+			},
+			// Timeout of the check, the default timeout is used if empty
+			"timeouts": Map{
+				"database": env("HEALTH_DATABASE_TIMEOUT", ""),
+				"cache":    env("HEALTH_CACHE_TIMEOUT", ""),
+				"queue":    env("HEALTH_QUEUE_TIMEOUT", ""),
+				"disk":     env("HEALTH_DISK_TIMEOUT", ""),
+				"jwt":      env("HEALTH_JWT_TIMEOUT", ""),
+			},
+		}
+	})
+}
+
+type HealthConfig struct {
+	Timeout       time.Duration
+	Token         string
+	DiskPath      string
+	DiskMinFreeMB int
+	Timeouts      map[string]time.Duration
+}
+
+func LoadHealthConfig(appConfig Config) (HealthConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := HealthConfig{
+		Timeout:       reader.Duration("health.timeout"),
+		Token:         reader.String("health.token"),
+		DiskPath:      reader.String("health.disk.path"),
+		DiskMinFreeMB: reader.Int("health.disk.min_free_mb"),
+		Timeouts:      map[string]time.Duration{},
+	}
+	if timeouts, ok := appConfig.Value("health.timeouts").(Map); ok {
+		for name := range timeouts {
+			config.Timeouts[name] = reader.Duration("health.timeouts." + name)
+		}
+	}
+
+	reader.Check(config.Timeout > 0, "health.timeout must be greater than 0")
+	reader.Check(config.DiskMinFreeMB >= 0, "health.disk.min_free_mb must not be negative")
+	for name, timeout := range config.Timeouts {
+		reader.Check(timeout >= 0, "health.timeouts."+name+" must not be negative")
+	}
+	return config, reader.Err()
+}
+
+// Make the registry with the checks of the database, cache, queue, disk and
+// JWT keys
+func NewHealthRegistry(appConfig Config, databases *connection.Manager) *health.Registry {
+	config, err := LoadHealthConfig(appConfig)
+	exception.PanicIfNeeded(err)
+	cache, err := LoadCacheConfig(appConfig)
+	exception.PanicIfNeeded(err)
+	queue, err := LoadQueueConfig(appConfig)
+	exception.PanicIfNeeded(err)
+
+	registry := health.NewRegistry(config.Timeout)
+	registry.Register(health.Check{
+		Name:    "database",
+		Timeout: config.Timeouts["database"],
+		Run:     databases.Ping,
+		Details: func() interface{} {
+			// Results of the periodic pings with the pool stats
+			results := []model.DatabaseHealthResponse{}
+			for _, result := range databases.Health() {
+				results = append(results, model.DatabaseHealthResponse{
+					Connection:      result.Connection,
+					Healthy:         result.Healthy,
+					Error:           result.Error,
+					LatencyMs:       float64(result.Latency.Microseconds()) / 1000,
+					CheckedAt:       result.CheckedAt,
+					Failures:        result.Failures,
+					OpenConnections: result.Stats.OpenConnections,
+					InUse:           result.Stats.InUse,
+					Idle:            result.Stats.Idle,
+					WaitCount:       result.Stats.WaitCount,
+				})
+			}
+			return results
+		},
+	})
+	registry.Register(health.Check{
+		Name:    "cache",
+		Timeout: config.Timeouts["cache"],
+		Run: func(ctx context.Context) error {
+			if cache.Driver != "redis" {
+				return nil
+			}
+			return health.PingRedis(ctx, net.JoinHostPort(cache.RedisHost, strconv.Itoa(cache.RedisPort)), cache.RedisPassword, cache.RedisDatabase)
+		},
+	})
+	registry.Register(health.Check{
+		Name:    "queue",
+		Timeout: config.Timeouts["queue"],
+		Run: func(ctx context.Context) error {
+			if queue.Driver != "redis" {
+				return nil
+			}
+			return health.PingRedis(ctx, net.JoinHostPort(queue.RedisHost, strconv.Itoa(queue.RedisPort)), queue.RedisPassword, queue.RedisDatabase)
+		},
+	})
+	registry.Register(health.Check{
+		Name:    "disk",
+		Timeout: config.Timeouts["disk"],
+		Run: func(ctx context.Context) error {
+			return health.CheckDisk(ctx, helper.BasePath(config.DiskPath), uint64(config.DiskMinFreeMB)<<20)
+		},
+	})
+	registry.Register(health.Check{
+		Name:    "jwt",
+		Timeout: config.Timeouts["jwt"],
+		Run: func(ctx context.Context) error {
+			return helper.CheckJWTKeys()
+		},
+	})
+	return registry
+}
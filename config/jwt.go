@@ -0,0 +1,36 @@
+package config
+
+import (
+	"govel/app/helper"
+	"os"
+)
+
+func init() {
+	Define("jwt", func(env Env) Map {
+		return Map{
+			"private_key_file": env("PRIVATE_KEY_FILE", "storage/app/ec256-private.pem"),
+			"public_key_file":  env("PUBLIC_KEY_FILE", "storage/app/ec256-public.pem"),
+		}
+	})
+}
+
+type JWTConfig struct {
+	PrivateKeyFile string
+	PublicKeyFile  string
+}
+
+func LoadJWTConfig(appConfig Config) (JWTConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := JWTConfig{
+		PrivateKeyFile: reader.String("jwt.private_key_file"),
+		PublicKeyFile:  reader.String("jwt.public_key_file"),
+	}
+
+	if _, err := os.Stat(helper.BasePath(config.PrivateKeyFile)); err != nil {
+		reader.Fail("jwt.private_key_file is not readable: " + err.Error())
+	}
+	if _, err := os.Stat(helper.BasePath(config.PublicKeyFile)); err != nil {
+		reader.Fail("jwt.public_key_file is not readable: " + err.Error())
+	}
+	return config, reader.Err()
+}
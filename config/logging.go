@@ -0,0 +1,93 @@
+package config
+
+import (
+	"govel/app/exception"
+	"govel/app/helper"
+	"govel/app/log"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+func init() {
+	Define("logging", func(env Env) Map {
+		return Map{
+			"default": env("LOG_CHANNEL", "stack"),
+			// Empty level is debug if APP_DEBUG, otherwise info
+			"level": nullable(env("LOG_LEVEL", "")),
+			"channels": Map{
+				"stack": Map{
+					"channels": env("LOG_STACK", "stdout,daily"),
+				},
+				"daily": Map{
+					"path": env("LOG_PATH", "storage/logs"),
+					"days": env("LOG_DAYS", "14"),
+				},
+			},
+		}
+	})
+}
+
+type LoggingConfig struct {
+	Channel string
+	Stack   []string
+	Level   slog.Level
+	Path    string
+	Days    int
+}
+
+func LoadLoggingConfig(appConfig Config) (LoggingConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := LoggingConfig{
+		Channel: reader.String("logging.default"),
+		Stack:   splitList(reader.String("logging.channels.stack.channels")),
+		Level:   slog.LevelInfo,
+		Path:    helper.BasePath(reader.String("logging.channels.daily.path")),
+		Days:    reader.Int("logging.channels.daily.days"),
+	}
+
+	channels := []string{"stdout", "json", "daily", "stack"}
+	reader.OneOf("logging.default", config.Channel, channels...)
+	if config.Channel == "stack" {
+		reader.Check(len(config.Stack) > 0, "logging.channels.stack.channels is required by the stack channel")
+		for _, channel := range config.Stack {
+			reader.OneOf("logging.channels.stack.channels", channel, channels[:3]...)
+		}
+	}
+	reader.Check(config.Days >= 0, "logging.channels.daily.days must not be negative")
+
+	if level := reader.String("logging.level"); level != "" {
+		parsed, err := log.ParseLevel(level)
+		reader.Check(err == nil, "logging.level must be one of debug, info, warn, error")
+		config.Level = parsed
+	} else if appConfig.GetBool("app.debug", false) {
+		config.Level = slog.LevelDebug
+	}
+	return config, reader.Err()
+}
+
+// Make the logger of LOG_CHANNEL, close the files with the closer on shutdown
+func NewLogger(appConfig Config) (*slog.Logger, io.Closer) {
+	config, err := LoadLoggingConfig(appConfig)
+	exception.PanicIfNeeded(err)
+
+	logger, closer, err := log.New(log.Config{
+		Channel: config.Channel,
+		Stack:   config.Stack,
+		Level:   config.Level,
+		Path:    config.Path,
+		Days:    config.Days,
+	})
+	exception.PanicIfNeeded(err)
+	return logger, closer
+}
+
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package config
+
+func init() {
+	Define("mail", func(env Env) Map {
+		return Map{
+			"default": env("MAIL_MAILER", "smtp"),
+			"mailers": Map{
+				"smtp": Map{
+					"host":       env("MAIL_HOST", "127.0.0.1"),
+					"port":       env("MAIL_PORT", "587"),
+					"username":   env("MAIL_USERNAME", ""),
+					"password":   env("MAIL_PASSWORD", ""),
+					"encryption": nullable(env("MAIL_ENCRYPTION", "")),
+				},
+			},
+		}
+	})
+}
+
+type MailConfig struct {
+	Mailer     string
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	Encryption string
+}
+
+func LoadMailConfig(appConfig Config) (MailConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := MailConfig{
+		Mailer:     reader.String("mail.default"),
+		Host:       reader.String("mail.mailers.smtp.host"),
+		Port:       reader.Int("mail.mailers.smtp.port"),
+		Username:   reader.String("mail.mailers.smtp.username"),
+		Password:   reader.String("mail.mailers.smtp.password"),
+		Encryption: reader.String("mail.mailers.smtp.encryption"),
+	}
+
+	reader.OneOf("mail.default", config.Mailer, "smtp", "sendmail", "log")
+	reader.OneOf("mail.mailers.smtp.encryption", config.Encryption, "", "tls", "ssl")
+	if config.Mailer == "smtp" {
+		reader.Check(config.Host != "", "mail.mailers.smtp.host is required by the smtp mailer")
+		reader.Check(config.Port > 0 && config.Port <= 65535, "mail.mailers.smtp.port must be between 1 and 65535")
+	}
+	return config, reader.Err()
+}
+
+// Treat the "null" value of the env file as empty
+func nullable(value string) string {
+	if value == "null" {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+func init() {
+	Define("metrics", func(env Env) Map {
+		return Map{
+			"enabled": env("METRICS_ENABLED", "false"),
+			// Path of the Prometheus scrape endpoint
+			"path": env("METRICS_PATH", "/metrics"),
+			// Bearer token of the scrape requests, anyone can scrape if empty
+			"token": nullable(env("METRICS_TOKEN", "")),
+		}
+	})
+}
+
+type MetricsConfig struct {
+	Enabled bool
+	Path    string
+	Token   string
+}
+
+func LoadMetricsConfig(appConfig Config) (MetricsConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := MetricsConfig{
+		Enabled: reader.Bool("metrics.enabled"),
+		Path:    reader.String("metrics.path"),
+		Token:   reader.String("metrics.token"),
+	}
+
+	reader.Check(strings.HasPrefix(config.Path, "/"), "metrics.path must start with /")
+	return config, reader.Err()
+}
+
+// Make the registry of the app with the runtime and the process metrics, the
+// database metrics are registered when the connections are opened
+func NewMetricsRegistry(appConfig Config) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return registry
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"govel/app/exception"
+	"govel/app/model"
+	"govel/app/openapi"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func init() {
+	Define("openapi", func(env Env) Map {
+		return Map{
+			// Empty serves the docs if APP_DEBUG, don't serve them publicly
+			"enabled": nullable(env("OPENAPI_ENABLED", "")),
+			// Path of the Swagger UI, the spec is served on {path}/openapi.json
+			"path": env("OPENAPI_PATH", "/api/docs"),
+			// Version of the API in the spec
+			"version": env("OPENAPI_VERSION", "1.0.0"),
+			// Base URL of the swagger-ui-dist assets, self host them if the
+			// browsers can't reach the CDN
+			"ui_assets_url": env("OPENAPI_UI_ASSETS_URL", "https://unpkg.com/swagger-ui-dist@5"),
+		}
+	})
+}
+
+type OpenAPIConfig struct {
+	Enabled     bool
+	Path        string
+	Version     string
+	UIAssetsURL string
+}
+
+func LoadOpenAPIConfig(appConfig Config) (OpenAPIConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := OpenAPIConfig{
+		Enabled:     appConfig.GetBool("app.debug", false),
+		Path:        strings.TrimSuffix(reader.String("openapi.path"), "/"),
+		Version:     reader.String("openapi.version"),
+		UIAssetsURL: reader.String("openapi.ui_assets_url"),
+	}
+
+	if reader.String("openapi.enabled") != "" {
+		config.Enabled = reader.Bool("openapi.enabled")
+	}
+	reader.Check(strings.HasPrefix(config.Path, "/"), "openapi.path must start with / and not be /")
+	reader.Check(config.Version != "", "openapi.version must not be empty")
+	return config, reader.Err()
+}
+
+// Get the generator of the spec of the API routes, the responses are wrapped
+// in the envelope and the errors are in the envelope or problem+json
+func NewOpenAPIGenerator(appConfig Config) *openapi.Generator {
+	return &openapi.Generator{
+		Registry: openapi.Default(),
+		Info: openapi.Info{
+			Title:   appConfig.Get("app.name"),
+			Version: appConfig.Get("openapi.version"),
+		},
+		Prefix:  "/api",
+		Exclude: []string{appConfig.Get("openapi.path")},
+		Envelope: func(data interface{}) interface{} {
+			return model.WebResponse{Code: 200, Message: "OK", Data: data}
+		},
+		Paginated: func(data interface{}) interface{} {
+			return model.PaginateResponse{Code: 200, Message: "OK", Data: data}
+		},
+		Errors: map[string]interface{}{
+			fiber.MIMEApplicationJSON:    model.WebResponse{},
+			exception.ProblemContentType: model.ProblemResponse{},
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"govel/app/exception"
+	"govel/app/queue"
+	"net"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func init() {
+	Define("queue", func(env Env) Map {
+		return Map{
+			"default": env("QUEUE_CONNECTION", "sync"),
+			// Times to try a failed job
+			"tries": env("QUEUE_TRIES", "3"),
+			// Work the jobs in the app process
+			"worker": env("QUEUE_WORKER", "true"),
+			"connections": Map{
+				"redis": Map{
+					"host":     env("REDIS_HOST", "127.0.0.1"),
+					"password": nullable(env("REDIS_PASSWORD", "")),
+					"port":     env("REDIS_PORT", "6379"),
+					"database": env("REDIS_QUEUE_DB", "0"),
+					"queue":    env("QUEUE_NAME", "default"),
+				},
+			},
+		}
+	})
+}
+
+type QueueConfig struct {
+	Driver        string
+	RedisHost     string
+	RedisPassword string
+	RedisPort     int
+	RedisDatabase int
+	Queue         string
+	Tries         int
+	Worker        bool
+}
+
+func LoadQueueConfig(appConfig Config) (QueueConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := QueueConfig{
+		Driver:        reader.String("queue.default"),
+		RedisHost:     reader.String("queue.connections.redis.host"),
+		RedisPassword: reader.String("queue.connections.redis.password"),
+		RedisPort:     reader.Int("queue.connections.redis.port"),
+		RedisDatabase: reader.Int("queue.connections.redis.database"),
+		Queue:         reader.String("queue.connections.redis.queue"),
+		Tries:         reader.Int("queue.tries"),
+		Worker:        reader.Bool("queue.worker"),
+	}
+
+	reader.OneOf("queue.default", config.Driver, "sync", "redis")
+	reader.Check(config.Tries >= 1, "queue.tries must be at least 1")
+	if config.Driver == "redis" {
+		reader.Check(config.RedisHost != "", "queue.connections.redis.host is required by the redis queue")
+		reader.Check(config.RedisPort > 0 && config.RedisPort <= 65535, "queue.connections.redis.port must be between 1 and 65535")
+		reader.Check(config.RedisDatabase >= 0, "queue.connections.redis.database must not be negative")
+		reader.Check(config.Queue != "", "queue.connections.redis.queue is required")
+	}
+	return config, reader.Err()
+}
+
+// Make the queue of QUEUE_CONNECTION, the sync queue runs the jobs at once
+func NewQueue(appConfig Config) *queue.Queue {
+	config, err := LoadQueueConfig(appConfig)
+	exception.PanicIfNeeded(err)
+
+	if config.Driver == "sync" {
+		return queue.NewSync()
+	}
+	return queue.New(queue.NewRedisDriver(&redis.Options{
+		Addr:     net.JoinHostPort(config.RedisHost, strconv.Itoa(config.RedisPort)),
+		Password: config.RedisPassword,
+		DB:       config.RedisDatabase,
+	}, config.Queue), config.Tries)
+}
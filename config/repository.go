@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"govel/app/encryption"
+	"govel/app/exception"
+	"govel/app/helper"
+	"os"
+	"sort"
+	"strings"
+)
+
+type Map = map[string]interface{}
+
+// Read the environment variable, the fallback is returned if empty
+type Env func(key string, fallback string) string
+
+type definition struct {
+	name    string
+	resolve func(env Env) Map
+}
+
+var definitions []definition
+
+// Register the config section resolved from the environment variables, e.g.
+// the "app" section is accessed with config.Get("app.name")
+func Define(name string, resolve func(env Env) Map) {
+	definitions = append(definitions, definition{name: name, resolve: resolve})
+}
+
+// Read the environment variable. The value is read from the file if
+// {KEY}_FILE is set, e.g. DB_PASSWORD_FILE=/run/secrets/db_password, and
+// decrypted with APP_KEY if it starts with "encrypted:".
+func env(key string, fallback string) string {
+	value := readEnv(key)
+	if strings.HasPrefix(value, encryption.Prefix) {
+		encrypter, err := encryption.FromAppKey(readEnv("APP_KEY"))
+		if err != nil {
+			exception.PanicIfNeeded(key + " is encrypted but APP_KEY is invalid: " + err.Error())
+		}
+		decrypted, err := encrypter.DecryptString(strings.TrimPrefix(value, encryption.Prefix))
+		if err != nil {
+			exception.PanicIfNeeded(key + " can't be decrypted with APP_KEY: " + err.Error())
+		}
+		value = decrypted
+	}
+
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func readEnv(key string) string {
+	filename := os.Getenv(key + "_FILE")
+	if filename == "" {
+		return os.Getenv(key)
+	}
+
+	content, err := os.ReadFile(helper.BasePath(filename))
+	exception.PanicIfNeeded(err)
+	return strings.TrimRight(string(content), "\r\n")
+}
+
+// Flatten the config to dot notation keys
+func Flatten(items Map) map[string]interface{} {
+	result := map[string]interface{}{}
+	var walk func(section Map, prefix string)
+	walk = func(section Map, prefix string) {
+		for key, value := range section {
+			if nested, ok := value.(Map); ok {
+				walk(nested, prefix+key+".")
+				continue
+			}
+			result[prefix+key] = value
+		}
+	}
+	walk(items, "")
+	return result
+}
+
+// Format the config as sorted "key = value" lines, the secrets are masked
+func Dump(items Map, prefix string) string {
+	flatten := Flatten(items)
+	keys := []string{}
+	for key := range flatten {
+		if prefix == "" || key == prefix || strings.HasPrefix(key, prefix+".") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := []string{}
+	for _, key := range keys {
+		value := fmt.Sprint(flatten[key])
+		if IsSecret(key) && value != "" {
+			value = "********"
+		}
+		lines = append(lines, key+" = "+value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Check the key holds a secret like password, token or key
+func IsSecret(key string) bool {
+	segments := strings.Split(strings.ToLower(key), ".")
+	last := segments[len(segments)-1]
+	for _, word := range []string{"password", "secret", "token", "key", "dsn", "url"} {
+		if last == word || strings.HasSuffix(last, "_"+word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Write the resolved config snapshot to the file
+func WriteCache(items Map, filename string) error {
+	content, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, 0600)
+}
+
+// Add the section values not set yet, e.g. the config defaults of a module.
+// The values from the config cache are kept.
+func Defaults(appConfig Config, name string, resolve func(env Env) Map) {
+	for key, value := range Flatten(resolve(env)) {
+		if appConfig.Value(name+"."+key) == nil {
+			appConfig.Set(name+"."+key, value)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"govel/app/exception"
+	"govel/app/session"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Define("session", func(env Env) Map {
+		return Map{
+			"driver":   env("SESSION_DRIVER", "cookie"),
+			"lifetime": env("SESSION_LIFETIME", "120"),
+			"cookie":   env("SESSION_COOKIE", "govel_session"),
+			"domain":   env("SESSION_DOMAIN", ""),
+			"secure":   env("SESSION_SECURE_COOKIE", "false"),
+		}
+	})
+}
+
+type SessionConfig struct {
+	Driver string
+	// Lifetime in minutes
+	Lifetime int
+	Cookie   string
+	Domain   string
+	Secure   bool
+}
+
+func LoadSessionConfig(appConfig Config) (SessionConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := SessionConfig{
+		Driver:   reader.String("session.driver"),
+		Lifetime: reader.Int("session.lifetime"),
+		Cookie:   reader.String("session.cookie"),
+		Domain:   reader.String("session.domain"),
+		Secure:   reader.Bool("session.secure"),
+	}
+
+	reader.OneOf("session.driver", config.Driver, "cookie", "database", "memory")
+	reader.Check(config.Lifetime > 0, "session.lifetime must be greater than 0")
+	if config.Driver == "cookie" {
+		reader.Check(appConfig.Get("app.key") != "", "app.key is required by the cookie session driver, run ./govel key:generate")
+	}
+	return config, reader.Err()
+}
+
+func NewSessionManager(appConfig Config, database *gorm.DB) *session.Manager {
+	config, err := LoadSessionConfig(appConfig)
+	exception.PanicIfNeeded(err)
+
+	var store session.Store
+	switch config.Driver {
+	case "cookie":
+		store = session.NewCookieStore(appConfig.Get("app.key"))
+	case "database":
+		store = session.NewDatabaseStore(database)
+	case "memory":
+		store = session.NewMemoryStore()
+	}
+
+	return &session.Manager{
+		Store:    store,
+		Cookie:   config.Cookie,
+		Lifetime: time.Duration(config.Lifetime) * time.Minute,
+		Path:     "/",
+		Domain:   config.Domain,
+		Secure:   config.Secure,
+	}
+}
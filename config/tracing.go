@@ -0,0 +1,123 @@
+package config
+
+import (
+	"context"
+	"govel/app/exception"
+	"govel/app/helper"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func init() {
+	Define("tracing", func(env Env) Map {
+		return Map{
+			// none, stdout, file or otlp
+			"exporter": env("TRACE_EXPORTER", "none"),
+			// Share of the new traces to export from 0 to 1
+			"sample_ratio": env("TRACE_SAMPLE_RATIO", "1"),
+			// Empty name is the app name
+			"service_name": nullable(env("OTEL_SERVICE_NAME", "")),
+			"file": Map{
+				"path": env("TRACE_FILE", "storage/logs/traces.log"),
+			},
+			"otlp": Map{
+				"endpoint": env("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"),
+				// Comma separated headers like "api-key=secret,tenant=acme"
+				"headers": nullable(env("OTEL_EXPORTER_OTLP_HEADERS", "")),
+				"timeout": env("OTEL_EXPORTER_OTLP_TIMEOUT", "10s"),
+			},
+		}
+	})
+}
+
+type TracingConfig struct {
+	Exporter     string
+	SampleRatio  float64
+	ServiceName  string
+	FilePath     string
+	OTLPEndpoint string
+	OTLPHeaders  map[string]string
+	OTLPTimeout  time.Duration
+}
+
+func LoadTracingConfig(appConfig Config) (TracingConfig, error) {
+	reader := newConfigReader(appConfig)
+	config := TracingConfig{
+		Exporter:     reader.String("tracing.exporter"),
+		SampleRatio:  reader.Float("tracing.sample_ratio"),
+		ServiceName:  reader.String("tracing.service_name"),
+		FilePath:     helper.BasePath(reader.String("tracing.file.path")),
+		OTLPEndpoint: reader.String("tracing.otlp.endpoint"),
+		OTLPHeaders:  map[string]string{},
+		OTLPTimeout:  reader.Duration("tracing.otlp.timeout"),
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = reader.String("app.name")
+	}
+	for _, header := range splitList(reader.String("tracing.otlp.headers")) {
+		key, value, ok := strings.Cut(header, "=")
+		reader.Check(ok && strings.TrimSpace(key) != "", "tracing.otlp.headers must be like key=value,key2=value2")
+		config.OTLPHeaders[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	reader.OneOf("tracing.exporter", config.Exporter, "none", "stdout", "file", "otlp")
+	reader.Check(config.SampleRatio >= 0 && config.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+	if config.Exporter == "otlp" {
+		reader.Check(strings.HasPrefix(config.OTLPEndpoint, "http://") || strings.HasPrefix(config.OTLPEndpoint, "https://"), "tracing.otlp.endpoint must be an http or https URL")
+		reader.Check(config.OTLPTimeout > 0, "tracing.otlp.timeout must be greater than 0")
+	}
+	return config, reader.Err()
+}
+
+// Make the tracer provider of TRACE_EXPORTER and its shutdown exporting the
+// queued spans, the no-op provider if none
+func NewTracerProvider(appConfig Config) (trace.TracerProvider, func(ctx context.Context) error) {
+	config, err := LoadTracingConfig(appConfig)
+	exception.PanicIfNeeded(err)
+
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	switch config.Exporter {
+	case "none":
+		return noop.NewTracerProvider(), func(ctx context.Context) error { return nil }
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "file":
+		exception.PanicIfNeeded(os.MkdirAll(filepath.Dir(config.FilePath), 0755))
+		file, err = os.OpenFile(config.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		exception.PanicIfNeeded(err)
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	case "otlp":
+		exporter, err = otlptracehttp.New(context.Background(),
+			otlptracehttp.WithEndpointURL(strings.TrimSuffix(config.OTLPEndpoint, "/")+"/v1/traces"),
+			otlptracehttp.WithHeaders(config.OTLPHeaders),
+			otlptracehttp.WithTimeout(config.OTLPTimeout),
+		)
+	}
+	exception.PanicIfNeeded(err)
+
+	// The new traces are sampled by the ratio, the traces of the callers by
+	// their sampled flag
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(config.ServiceName))),
+	)
+	return provider, func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			file.Close()
+		}
+		return err
+	}
+}
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"govel/app/connection"
+	"govel/app/console"
+	"govel/app/encryption"
+	"govel/app/hashing"
+	"govel/app/module"
+	"govel/bootstrap"
 	"govel/config"
-	"govel/database/migration"
-	"govel/database/seeder"
 	"os"
+
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -12,14 +18,34 @@ func main() {
 	appConfig := config.New()
 	appConfig.LoadEnv()
 
-	// Setup Database
-	database := config.NewDatabase(appConfig)
+	// Setup the encrypter and the hasher of the entity columns
+	encryption.SetDefault(config.NewEncrypter(appConfig))
+	hashing.SetDefault(config.NewHasher(appConfig))
+
+	// Setup the modules of the entities and the seeders
+	modules := bootstrap.Modules()
+	module.LoadConfig(appConfig, modules)
 
-	if len(os.Args) > 1 {
-		if os.Args[1] == "start" {
-			migration.Migrator(database)
-		} else if os.Args[1] == "seed" {
-			seeder.Seeder(database)
-		}
+	// Setup Database, the schema is read and changed on the primary
+	database := func() *gorm.DB {
+		return connection.UsePrimary(config.NewDatabase(appConfig))
 	}
+
+	console.Run([]console.Command{
+		{
+			Name:        "start",
+			Description: "Run the migrations",
+			Handle: func(args []string) error {
+				return module.Migrate(database(), modules)
+			},
+		},
+		{
+			Name:        "seed",
+			Description: "Create fake data",
+			Handle: func(args []string) error {
+				return module.Seed(database(), modules)
+			},
+		},
+		console.DBWaitCommand(appConfig),
+	}, os.Args[1:])
 }
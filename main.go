@@ -1,9 +1,17 @@
 package main
 
 import (
+	"govel/app/console"
 	"govel/app/exception"
+	"govel/app/lifecycle"
+	"govel/app/module"
 	"govel/bootstrap"
 	"govel/config"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
 )
 
 func main() {
@@ -11,9 +19,23 @@ func main() {
 	configuration := config.New()
 	configuration.LoadEnv()
 
-	app := bootstrap.Make(configuration)
+	// Add the config defaults of the modules
+	modules := bootstrap.Modules()
+	module.LoadConfig(configuration, modules)
+
+	// Run the console command if given, e.g. ./govel env:check
+	if len(os.Args) > 1 {
+		commands := append(console.Commands(configuration), module.Commands(configuration, modules)...)
+		commands = append(commands, console.OpenAPIExportCommand(configuration, func() (*fiber.App, *lifecycle.Lifecycle) {
+			return bootstrap.Routes(configuration)
+		}))
+		console.Run(commands, os.Args[1:])
+		return
+	}
+
+	app, hooks := bootstrap.Make(configuration)
 
-	// Start App
-	err := app.Listen(":" + configuration.Get("APP_PORT"))
+	// Start App, drain the requests and close the connections on SIGTERM
+	err := bootstrap.Serve(app, hooks, ":"+strconv.Itoa(configuration.GetInt("app.port", 8000)), configuration.GetDuration("app.shutdown_timeout", 30*time.Second))
 	exception.PanicIfNeeded(err)
 }
@@ -1,23 +1,14 @@
 package route
 
 import (
-	"govel/app/http/controller"
-	"govel/app/repository"
-	"govel/app/service"
+	"govel/app/container"
 
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/gorm"
 )
 
+// Routes of the app outside the modules, the routes of a domain are
+// registered by its module
+//
 // Doc route rules https://docs.gofiber.io/
-func APIRoute(route fiber.Router, database *gorm.DB) {
-	// Setup Repository
-	userRepository := repository.NewUserRepository(database)
-
-	// Setup Service
-	userService := service.NewUserService(&userRepository)
-
-	// Setup Controller
-	userController := controller.NewUserController(&userService)
-	userController.Route(route)
+func APIRoute(route fiber.Router, c *container.Container) {
 }
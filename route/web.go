@@ -1,10 +1,17 @@
 package route
 
 import (
+	"govel/app/container"
+	"govel/app/helper"
+	"govel/app/http/middleware"
+
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/gorm"
 )
 
-func WebRoute(route fiber.Router, database *gorm.DB) {
-	route.Static("/", "./public").Name("root")
+// The session is started here for the web routes of every module. Only the
+// routes registered through the returned router start it, so the static
+// files and the 404 responses don't set the session cookie.
+func WebRoute(route fiber.Router, c *container.Container) fiber.Router {
+	route.Static("/", helper.BasePath("public")).Name("root")
+	return middleware.PerRoute(route, middleware.StartSession, middleware.VerifyCsrfToken)
 }
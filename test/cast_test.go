@@ -0,0 +1,45 @@
+package test
+
+import (
+	"govel/app/cast"
+	"govel/app/encryption"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCast_Encrypted(t *testing.T) {
+	previous := encryption.Default()
+	defer encryption.SetDefault(previous)
+	encrypter, _ := encryption.FromAppKey(encryption.GenerateKey())
+	encryption.SetDefault(encrypter)
+
+	value, err := cast.Encrypted[[]string]{Val: []string{"a", "b"}}.Value()
+	assert.Nil(t, err)
+	assert.NotEqual(t, `["a","b"]`, value)
+	assert.True(t, strings.HasPrefix(value.(string), encryption.Prefix))
+
+	// The stored value decrypts back to the original
+	decrypted, err := encrypter.DecryptString(strings.TrimPrefix(value.(string), encryption.Prefix))
+	assert.Nil(t, err)
+	assert.Equal(t, `["a","b"]`, decrypted)
+
+	scanned := cast.Encrypted[[]string]{}
+	assert.Nil(t, scanned.Scan(value))
+	assert.Equal(t, []string{"a", "b"}, scanned.Val)
+
+	// The value stored before the column was encrypted
+	legacy := cast.Encrypted[string]{}
+	assert.Nil(t, legacy.Scan([]byte("plain@example.com")))
+	assert.Equal(t, "plain@example.com", legacy.Val)
+
+	// The encrypted value with other key is an error, not the ciphertext
+	other, _ := encryption.FromAppKey(encryption.GenerateKey())
+	invalid := cast.Encrypted[string]{}
+	assert.Error(t, invalid.Scan(encryption.Prefix+other.EncryptString("secret")))
+	assert.Empty(t, invalid.Val)
+
+	assert.Equal(t, cast.BlindIndex("a@example.com"), cast.BlindIndex("a@example.com"))
+	assert.NotEqual(t, cast.BlindIndex("a@example.com"), cast.BlindIndex("b@example.com"))
+}
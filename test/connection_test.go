@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"govel/app/connection"
+	"govel/app/container"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type stickyRow struct {
+	ID uint
+}
+
+func TestConnection_StickyScope(t *testing.T) {
+	dir := t.TempDir()
+	primary, err := gorm.Open(sqlite.Open(dir+"/primary.db"), &gorm.Config{})
+	assert.NoError(t, err)
+	replica, err := gorm.Open(sqlite.Open(dir+"/replica.db"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, primary.AutoMigrate(&stickyRow{}))
+	assert.NoError(t, replica.AutoMigrate(&stickyRow{}))
+	replicaPool, _ := replica.DB()
+	assert.NoError(t, primary.Use(connection.NewResolver([]*sql.DB{replicaPool}, true)))
+
+	// Bound like the *gorm.DB of the app, scoped to the request context
+	c := container.New()
+	container.Scoped(c, func(c *container.Container) *gorm.DB {
+		return primary.WithContext(c.Context())
+	})
+
+	// The reads go to the replica until the request writes
+	request := container.Make[*gorm.DB](c.Scope(connection.WithSticky(context.Background())))
+	var count int64
+	request.Model(&stickyRow{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+	assert.NoError(t, request.Create(&stickyRow{}).Error)
+	request.Model(&stickyRow{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// Other request reads from the replica
+	other := container.Make[*gorm.DB](c.Scope(connection.WithSticky(context.Background())))
+	other.Model(&stickyRow{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestConnection_Retry(t *testing.T) {
+	// Zero timeout tries once
+	attempts := 0
+	err := connection.Retry(0, func(attempt int) error {
+		attempts++
+		return errors.New("refused")
+	})
+	assert.EqualError(t, err, "refused")
+	assert.Equal(t, 1, attempts)
+
+	// Back off from 200ms doubling, with up to 20% jitter, until the deadline
+	// and return the last error
+	times := []time.Time{}
+	err = connection.Retry(time.Second, func(attempt int) error {
+		times = append(times, time.Now())
+		return fmt.Errorf("attempt %d", attempt)
+	})
+	assert.EqualError(t, err, "attempt 3")
+	assert.Len(t, times, 3)
+	assert.GreaterOrEqual(t, times[1].Sub(times[0]), 200*time.Millisecond)
+	assert.Less(t, times[1].Sub(times[0]), 400*time.Millisecond)
+	assert.GreaterOrEqual(t, times[2].Sub(times[1]), 400*time.Millisecond)
+
+	// Stop on success
+	err = connection.Retry(time.Second, func(attempt int) error {
+		if attempt < 2 {
+			return errors.New("refused")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestConnection_HealthChecks(t *testing.T) {
+	dir := t.TempDir()
+	manager := connection.NewManager("default", func(name string) *gorm.DB {
+		db, err := gorm.Open(sqlite.Open(dir+"/"+name+".db"), &gorm.Config{})
+		assert.NoError(t, err)
+		return db
+	})
+	manager.Connection("default")
+
+	// Not ready before the first check
+	assert.False(t, manager.Ready())
+	assert.Nil(t, manager.Health())
+
+	manager.StartHealthChecks(time.Hour, time.Second)
+	defer manager.StopHealthChecks()
+	assert.True(t, manager.Ready())
+	health := manager.Health()
+	assert.Len(t, health, 1)
+	assert.Equal(t, "default", health[0].Connection)
+	assert.True(t, health[0].Healthy)
+	assert.Equal(t, int64(1), health[0].Pings)
+
+	// The closed pool fails the ping
+	sqlDB, _ := manager.Connection("default").DB()
+	sqlDB.Close()
+	manager.CheckHealth()
+	assert.False(t, manager.Ready())
+	health = manager.Health()
+	assert.False(t, health[0].Healthy)
+	assert.NotEmpty(t, health[0].Error)
+	assert.Equal(t, int64(1), health[0].ConsecutiveFailures)
+	assert.Equal(t, int64(2), health[0].Pings)
+}
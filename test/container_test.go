@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"govel/app/container"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type counter struct{ id int }
+
+type greeter interface{ Greet() string }
+
+type englishGreeter struct{ counter *counter }
+
+func (greeter englishGreeter) Greet() string { return "hello" }
+
+type fakeGreeter struct{}
+
+func (greeter fakeGreeter) Greet() string { return "fake" }
+
+func TestContainer_Lifetimes(t *testing.T) {
+	c := container.New()
+	made := 0
+	container.Scoped(c, func(c *container.Container) *counter {
+		made++
+		return &counter{id: made}
+	})
+	container.Transient(c, func(c *container.Container) greeter {
+		return englishGreeter{counter: container.Make[*counter](c)}
+	})
+
+	// Scoped is shared in the scope only
+	first := c.Scope(context.Background())
+	second := c.Scope(context.Background())
+	assert.Same(t, container.Make[*counter](first), container.Make[*counter](first))
+	assert.NotSame(t, container.Make[*counter](first), container.Make[*counter](second))
+	assert.Equal(t, 2, made)
+
+	// Transient uses the scoped of its scope
+	assert.Same(t, container.Make[*counter](first), container.Make[greeter](first).(englishGreeter).counter)
+
+	// Scoped can't be resolved from the root
+	_, err := container.Resolve[*counter](c)
+	assert.Error(t, err)
+}
+
+func TestContainer_Singleton(t *testing.T) {
+	c := container.New()
+	container.Singleton(c, func(c *container.Container) *counter { return &counter{} })
+
+	scope := c.Scope(context.Background())
+	assert.Same(t, container.Make[*counter](c), container.Make[*counter](scope))
+}
+
+func TestContainer_Swap(t *testing.T) {
+	c := container.New()
+	container.Transient(c, func(c *container.Container) greeter { return englishGreeter{} })
+	container.Swap[greeter](c, fakeGreeter{})
+
+	assert.Equal(t, "fake", container.Make[greeter](c.Scope(context.Background())).Greet())
+}
+
+func TestContainer_Validate(t *testing.T) {
+	c := container.New()
+	container.Transient(c, func(c *container.Container) greeter {
+		return englishGreeter{counter: container.Make[*counter](c)}
+	})
+
+	_, err := container.Resolve[greeter](c)
+	assert.EqualError(t, err, "No binding for *test.counter required by test.greeter")
+	assert.EqualError(t, c.Validate(), "Invalid container bindings:\n- No binding for *test.counter required by test.greeter")
+
+	container.Instance(c, &counter{})
+	assert.NoError(t, c.Validate())
+}
@@ -1,18 +1,26 @@
 package test
 
 import (
+	"govel/app/container"
 	"govel/bootstrap"
 	"govel/config"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func CreateApplication() (app *fiber.App) {
-	// Setup Configuration
+// Create the app, the overrides can swap the bindings with fakes, e.g.
+// container.Swap[service.LoginThrottle](c, fake)
+func CreateApplication(overrides ...func(c *container.Container)) (app *fiber.App) {
+	// Setup Configuration, load .env and .env.test from the project root
+	os.Setenv("APP_ENV", "test")
+	// Serve the docs to test the spec
+	os.Setenv("OPENAPI_ENABLED", "true")
 	configuration := config.New()
-	configuration.LoadEnv("../.env.test")
+	configuration.LoadEnv()
 
-	return bootstrap.Make(configuration)
+	app, _ = bootstrap.Make(configuration, overrides...)
+	return app
 }
 
 var app = CreateApplication()
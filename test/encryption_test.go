@@ -0,0 +1,32 @@
+package test
+
+import (
+	"govel/app/encryption"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryption_EncryptDecrypt(t *testing.T) {
+	encrypter, err := encryption.FromAppKey(encryption.GenerateKey())
+	assert.Nil(t, err)
+
+	payload := encrypter.EncryptString("rahasia")
+	assert.NotEqual(t, payload, encrypter.EncryptString("rahasia"))
+	value, err := encrypter.DecryptString(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, "rahasia", value)
+
+	// Other key
+	other, _ := encryption.FromAppKey(encryption.GenerateKey())
+	_, err = other.DecryptString(payload)
+	assert.Equal(t, encryption.ErrInvalidPayload, err)
+}
+
+func TestEncryption_InvalidKey(t *testing.T) {
+	_, err := encryption.FromAppKey("base64:c2hvcnQ=")
+	assert.NotNil(t, err)
+
+	_, err = encryption.FromAppKey("12345678901234567890123456789012")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"govel/app/connection"
+	"govel/app/exception"
+	"govel/app/http/middleware"
+	"govel/app/model"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newErrorApp() *fiber.App {
+	errorApp := fiber.New(fiber.Config{ErrorHandler: exception.ErrorHandler})
+	errorApp.Get("/fail", func(c *fiber.Ctx) error {
+		return errors.New("Error 1064: near MATCH (name) AGAINST")
+	})
+	return errorApp
+}
+
+func TestErrorHandler_Production(t *testing.T) {
+	reports := []exception.Report{}
+	removeReporter := exception.AddReporter(exception.ReporterFunc(func(ctx context.Context, report exception.Report) {
+		reports = append(reports, report)
+	}))
+	defer removeReporter()
+	defer exception.SetDebug(exception.Debug())
+	exception.SetDebug(false)
+
+	response, _ := newErrorApp().Test(httptest.NewRequest("GET", "/fail?password=secret", nil))
+	body := model.WebResponse{}
+	json.NewDecoder(response.Body).Decode(&body)
+
+	// The internals are hidden, the error ID is reported
+	assert.Equal(t, 500, response.StatusCode)
+	assert.NotContains(t, body.Data, "MATCH")
+	assert.NotEmpty(t, body.ErrorID)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, body.ErrorID, reports[0].ID)
+	assert.EqualError(t, reports[0].Err, "Error 1064: near MATCH (name) AGAINST")
+}
+
+func TestErrorHandler_Debug(t *testing.T) {
+	defer exception.SetDebug(exception.Debug())
+	exception.SetDebug(true)
+
+	database, err := gorm.Open(sqlite.Open(t.TempDir()+"/debug.db"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	assert.NoError(t, database.Use(&connection.QueryLogger{}))
+
+	// The failed query and the stack of the panic are responded
+	errorApp := fiber.New(fiber.Config{ErrorHandler: exception.ErrorHandler})
+	errorApp.Use(recover.New(recover.Config{EnableStackTrace: true, StackTraceHandler: exception.SaveStack}))
+	errorApp.Use(middleware.InspectQueries(0))
+	errorApp.Get("/fail", func(c *fiber.Ctx) error {
+		err := database.WithContext(c.UserContext()).Exec("SELECT * FROM missing WHERE id = ?", 7).Error
+		exception.PanicIfNeeded(err)
+		return nil
+	})
+
+	response, _ := errorApp.Test(httptest.NewRequest("GET", "/fail?password=secret&page=2", nil))
+	body := struct {
+		Data model.DebugError `json:"data"`
+	}{}
+	json.NewDecoder(response.Body).Decode(&body)
+
+	assert.Equal(t, 500, response.StatusCode)
+	assert.Contains(t, body.Data.Message, "no such table: missing")
+	assert.Equal(t, "SELECT * FROM missing WHERE id = 7", body.Data.SQL)
+	assert.NotEmpty(t, body.Data.Stack)
+	assert.Contains(t, strings.Join(body.Data.Stack, "\n"), "error_handler_test.go")
+	assert.Equal(t, "GET", body.Data.Request.Method)
+	assert.Equal(t, map[string]string{"password": "[hidden]", "page": "2"}, body.Data.Request.Query)
+}
+
+func TestErrorHandler_Problem(t *testing.T) {
+	errorApp := fiber.New(fiber.Config{ErrorHandler: exception.ErrorHandler})
+	errorApp.Use(recover.New())
+	errorApp.Post("/register", func(c *fiber.Ctx) error {
+		panic(exception.ValidationError{
+			Message: "email: cannot be blank.",
+			Fields:  map[string]string{"email": "cannot be blank"},
+		})
+	})
+	defer exception.SetRenderer(exception.CurrentRenderer())
+	exception.SetRenderer(exception.NegotiatingRenderer{
+		Default: exception.EnvelopeRenderer{},
+		Problem: exception.ProblemRenderer{TypeBaseURL: "https://example.com/errors"},
+	})
+
+	request := httptest.NewRequest("POST", "/register", nil)
+	request.Header.Set("Accept", "application/problem+json")
+	response, _ := errorApp.Test(request)
+	body := model.ProblemResponse{}
+	json.NewDecoder(response.Body).Decode(&body)
+
+	assert.Equal(t, 400, response.StatusCode)
+	assert.Equal(t, exception.ProblemContentType, response.Header.Get("Content-Type"))
+	assert.Equal(t, "https://example.com/errors/validation-error", body.Type)
+	assert.Equal(t, map[string]string{"email": "cannot be blank"}, body.Errors)
+}
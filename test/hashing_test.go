@@ -0,0 +1,39 @@
+package test
+
+import (
+	"govel/app/hashing"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHasher(driver string, rounds int) hashing.Hasher {
+	return hashing.New(
+		driver,
+		hashing.NewBcryptHasher(rounds),
+		hashing.NewArgon2idHasher(hashing.Argon2idOptions{Memory: 1024, Time: 1, Threads: 1}),
+	)
+}
+
+func TestHashing_Check(t *testing.T) {
+	for _, driver := range []string{"bcrypt", "argon2id"} {
+		hasher := newTestHasher(driver, 4)
+		hashed := hasher.Make("rahasia")
+		assert.Equal(t, driver, hashing.Algorithm(hashed))
+		assert.True(t, hasher.Check("rahasia", hashed))
+		assert.False(t, hasher.Check("wrong", hashed))
+		assert.False(t, hasher.NeedsRehash(hashed))
+	}
+}
+
+func TestHashing_NeedsRehash(t *testing.T) {
+	hashed := newTestHasher("bcrypt", 4).Make("rahasia")
+
+	// Cost changed
+	assert.True(t, newTestHasher("bcrypt", 5).NeedsRehash(hashed))
+
+	// Algorithm changed, the old hash still can be checked
+	argon := newTestHasher("argon2id", 4)
+	assert.True(t, argon.NeedsRehash(hashed))
+	assert.True(t, argon.Check("rahasia", hashed))
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"govel/app/container"
+	"govel/app/health"
+	"govel/app/http/controller"
+	"govel/app/http/middleware"
+	"govel/app/lifecycle"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth_Registry(t *testing.T) {
+	registry := health.NewRegistry(50 * time.Millisecond)
+	registry.Register(health.Check{Name: "ok", Kind: health.Liveness, Run: func(ctx context.Context) error { return nil }})
+	registry.Register(health.Check{Name: "failed", Run: func(ctx context.Context) error { return errors.New("down") }})
+	registry.Register(health.Check{Name: "slow", Run: func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}})
+
+	// Liveness runs the liveness checks only
+	live := registry.Run(context.Background(), health.Liveness)
+	assert.True(t, live.OK())
+	assert.Len(t, live.Checks, 1)
+
+	ready := registry.Run(context.Background(), health.Readiness)
+	assert.False(t, ready.OK())
+	assert.Len(t, ready.Checks, 3)
+	assert.Equal(t, "down", ready.Checks[0].Error)
+	assert.Equal(t, "timed out after 50ms", ready.Checks[2].Error)
+}
+
+func TestHealthController_Details(t *testing.T) {
+	c := container.New()
+	registry := health.NewRegistry(time.Second)
+	registry.Register(health.Check{Name: "cache", Run: func(ctx context.Context) error {
+		return errors.New("dial tcp 10.0.0.5:6379: connect: connection refused")
+	}})
+	container.Instance(c, registry)
+	container.Instance(c, lifecycle.New())
+
+	healthApp := fiber.New()
+	healthApp.Use(middleware.StartScope(c))
+	healthController := controller.NewHealthController(false, "secret")
+	healthController.Route(healthApp)
+
+	// The status only without the token
+	response, _ := healthApp.Test(httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, 503, response.StatusCode)
+	body, _ := io.ReadAll(response.Body)
+	assert.NotContains(t, string(body), "10.0.0.5")
+
+	request := httptest.NewRequest("GET", "/readyz", nil)
+	request.Header.Set("Authorization", "Bearer secret")
+	response, _ = healthApp.Test(request)
+	body, _ = io.ReadAll(response.Body)
+	assert.Contains(t, string(body), "10.0.0.5")
+}
+
+func TestHealthController_ShuttingDown(t *testing.T) {
+	c := container.New()
+	container.Instance(c, health.NewRegistry(time.Second))
+	hooks := lifecycle.New()
+	container.Instance(c, hooks)
+
+	healthApp := fiber.New()
+	healthApp.Use(middleware.StartScope(c))
+	healthController := controller.NewHealthController(false, "")
+	healthController.Route(healthApp)
+
+	response, _ := healthApp.Test(httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, 200, response.StatusCode)
+
+	// Not ready once the shutdown started, still alive
+	hooks.Shutdown(context.Background())
+	response, _ = healthApp.Test(httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, 503, response.StatusCode)
+	response, _ = healthApp.Test(httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, 200, response.StatusCode)
+}
@@ -0,0 +1,33 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"govel/app/lifecycle"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycle_Shutdown(t *testing.T) {
+	hooks := lifecycle.New()
+	order := []string{}
+	hooks.OnShutdown("database", func(ctx context.Context) error {
+		order = append(order, "database")
+		return nil
+	})
+	hooks.OnShutdown("queue", func(ctx context.Context) error {
+		order = append(order, "queue")
+		return errors.New("timeout")
+	})
+
+	// Reverse order, every hook runs even if one failed
+	err := hooks.Shutdown(context.Background())
+	assert.Equal(t, []string{"queue", "database"}, order)
+	assert.EqualError(t, err, "Shutdown failed:\n- queue: timeout")
+	assert.True(t, hooks.ShuttingDown())
+
+	// Only once
+	assert.Nil(t, hooks.Shutdown(context.Background()))
+	assert.Len(t, order, 2)
+}
@@ -0,0 +1,47 @@
+package test
+
+import (
+	"govel/app/log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_DailyWriter(t *testing.T) {
+	path := t.TempDir()
+	os.WriteFile(filepath.Join(path, "govel-2023-01-01.log"), []byte("old\n"), 0644)
+	os.WriteFile(filepath.Join(path, "govel-2023-01-09.log"), []byte("kept\n"), 0644)
+
+	writer, err := log.NewDailyWriter(path, "govel", 3)
+	assert.NoError(t, err)
+	now := time.Date(2023, 1, 10, 23, 59, 0, 0, time.UTC)
+	writer.Now = func() time.Time { return now }
+
+	writer.Write([]byte("first\n"))
+	now = now.Add(time.Minute)
+	writer.Write([]byte("second\n"))
+	assert.NoError(t, writer.Close())
+
+	// Rotated on the next day, the files older than 3 days are deleted
+	files, _ := filepath.Glob(filepath.Join(path, "*.log"))
+	assert.Equal(t, []string{
+		filepath.Join(path, "govel-2023-01-09.log"),
+		filepath.Join(path, "govel-2023-01-10.log"),
+		filepath.Join(path, "govel-2023-01-11.log"),
+	}, files)
+	content, _ := os.ReadFile(filepath.Join(path, "govel-2023-01-11.log"))
+	assert.Equal(t, "second\n", string(content))
+}
+
+func TestLog_ParseLevel(t *testing.T) {
+	level, err := log.ParseLevel("WARN")
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+
+	_, err = log.ParseLevel("verbose")
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"govel/app/connection"
+	"govel/app/exception"
+	"govel/app/http/controller"
+	"govel/app/http/middleware"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Scrape the registry like Prometheus does
+func scrapeMetrics(t *testing.T, registry *prometheus.Registry) string {
+	metricsApp := fiber.New()
+	metricsController := controller.NewMetricsController(registry, "/metrics", "")
+	metricsController.Route(metricsApp)
+
+	response, err := metricsApp.Test(httptest.NewRequest("GET", "/metrics", nil))
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(response.Body)
+	return string(body)
+}
+
+func TestMetrics_RouteTemplate(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metricsApp := fiber.New()
+	metricsApp.Use(middleware.Metrics(registry))
+	metricsApp.Get("/users/:id", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	metricsApp.Use(middleware.NotFound)
+
+	metricsApp.Test(httptest.NewRequest("GET", "/users/1", nil))
+	metricsApp.Test(httptest.NewRequest("GET", "/users/2", nil))
+	metricsApp.Test(httptest.NewRequest("GET", "/missing", nil))
+
+	output := scrapeMetrics(t, registry)
+	assert.Contains(t, output, `http_requests_total{method="GET",route="/users/:id",status="200"} 2`)
+	assert.Contains(t, output, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+}
+
+func TestMetrics_Database(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	database, err := gorm.Open(sqlite.Open(t.TempDir()+"/metrics.db"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	assert.NoError(t, database.Use(&connection.Metrics{Connection: "sqlite", Registerer: registry}))
+
+	// Another connection shares the query metrics
+	other, err := gorm.Open(sqlite.Open(t.TempDir()+"/other.db"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	assert.NoError(t, other.Use(&connection.Metrics{Connection: "other", Registerer: registry}))
+
+	database.Exec("SELECT 1")
+	database.Exec("SELECT * FROM missing")
+
+	output := scrapeMetrics(t, registry)
+	assert.Contains(t, output, `db_query_duration_seconds_count{connection="sqlite",operation="raw",table=""} 2`)
+	assert.Contains(t, output, `db_query_errors_total{connection="sqlite",operation="raw",table=""} 1`)
+	assert.Contains(t, output, `go_sql_open_connections{db_name="other"}`)
+}
+
+func TestMetrics_Token(t *testing.T) {
+	metricsApp := fiber.New(fiber.Config{ErrorHandler: exception.ErrorHandler})
+	metricsController := controller.NewMetricsController(prometheus.NewRegistry(), "/metrics", "secret")
+	metricsController.Route(metricsApp)
+
+	response, _ := metricsApp.Test(httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 401, response.StatusCode)
+
+	request := httptest.NewRequest("GET", "/metrics", nil)
+	request.Header.Set("Authorization", "Bearer wrong")
+	response, _ = metricsApp.Test(request)
+	assert.Equal(t, 401, response.StatusCode)
+
+	request = httptest.NewRequest("GET", "/metrics", nil)
+	request.Header.Set("Authorization", "Bearer secret")
+	response, _ = metricsApp.Test(request)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Contains(t, response.Header.Get("Content-Type"), "text/plain")
+}
@@ -0,0 +1,61 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"govel/app/openapi"
+	"govel/bootstrap"
+	"govel/config"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI_Spec(t *testing.T) {
+	response, _ := app.Test(httptest.NewRequest("GET", "/api/docs/openapi.json", nil))
+	assert.Equal(t, 200, response.StatusCode)
+
+	responseBody, _ := io.ReadAll(response.Body)
+	document := openapi.Document{}
+	assert.NoError(t, json.Unmarshal(responseBody, &document))
+	assert.Equal(t, openapi.Version, document.OpenAPI)
+	assert.NotContains(t, document.Paths, "/api/docs")
+
+	// The form fields are required by the validation
+	register := document.Paths["/api/v1/users/register"]["post"]
+	form := register.RequestBody.Content["application/x-www-form-urlencoded"].Schema
+	assert.Contains(t, form.Properties, "social_id")
+	assert.Equal(t, []string{"email", "name", "password", "repassword"}, form.Required)
+	assert.Empty(t, register.Security)
+
+	// The path parameter is typed by the request model
+	show := document.Paths["/api/v1/users/{id}"]["get"]
+	assert.Len(t, show.Parameters, 1)
+	assert.Equal(t, "path", show.Parameters[0].In)
+	assert.Equal(t, "integer", show.Parameters[0].Schema.Type)
+
+	// The group middleware secures the routes
+	tokens := document.Paths["/api/v1/tokens"]["get"]
+	assert.Equal(t, []map[string][]string{{"bearer": {}}}, tokens.Security)
+	assert.Equal(t, "http", document.Components.SecuritySchemes["bearer"].Type)
+}
+
+func TestOpenAPI_RoutesWithoutConnections(t *testing.T) {
+	// Connecting fails at once, the routes are registered without it
+	t.Setenv("DB_CONNECTION", "mysql")
+	t.Setenv("DB_HOST", "127.0.0.1")
+	t.Setenv("DB_PORT", "1")
+	t.Setenv("DB_CONNECT_TIMEOUT", "0")
+	configuration := config.New()
+	configuration.LoadEnv()
+
+	assert.NotPanics(t, func() {
+		routesApp, hooks := bootstrap.Routes(configuration)
+		defer hooks.Shutdown(context.Background())
+
+		document := config.NewOpenAPIGenerator(configuration).Generate(routesApp)
+		assert.Contains(t, document.Paths, "/api/v1/users/login")
+	})
+}
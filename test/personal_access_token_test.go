@@ -0,0 +1,64 @@
+package test
+
+import (
+	"encoding/json"
+	"govel/app/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mintance/go-uniqid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Post the form with the bearer token, decode the data of the response
+func postForm(t *testing.T, path string, form string, token string, data interface{}) *http.Response {
+	request := httptest.NewRequest("POST", path, strings.NewReader(form))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := app.Test(request)
+	assert.NoError(t, err)
+
+	webResponse := model.WebResponse{Data: data}
+	json.NewDecoder(response.Body).Decode(&webResponse)
+	return response
+}
+
+func TestPersonalAccessTokenController_Abilities(t *testing.T) {
+	email := uniqid.New(uniqid.Params{Prefix: "govel", MoreEntropy: true}) + "@gmail.com"
+	postForm(t, "/api/v1/users/register", "email="+email+"&name=Token Owner&password=rahasia&repassword=rahasia", "", nil)
+	login := model.TokenResponse{}
+	response := postForm(t, "/api/v1/users/login", "email="+email+"&password=rahasia", "", &login)
+	assert.Equal(t, 200, response.StatusCode)
+
+	// The JWT can grant any ability
+	writer := model.CreatePersonalAccessTokenResponse{}
+	response = postForm(t, "/api/v1/tokens/create", "name=writer&abilities=tokens:write", login.Token, &writer)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"tokens:write"}, writer.Abilities)
+
+	// The token can't grant more than its abilities
+	response = postForm(t, "/api/v1/tokens/create", "name=admin&abilities=*", writer.PlainTextToken, nil)
+	assert.Equal(t, 403, response.StatusCode)
+	response = postForm(t, "/api/v1/tokens/create", "name=reader&abilities=tokens:read", writer.PlainTextToken, nil)
+	assert.Equal(t, 403, response.StatusCode)
+
+	// The abilities of the token by default
+	copied := model.CreatePersonalAccessTokenResponse{}
+	response = postForm(t, "/api/v1/tokens/create", "name=copy", writer.PlainTextToken, &copied)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"tokens:write"}, copied.Abilities)
+
+	// Missing the ability of the route is forbidden
+	request := httptest.NewRequest("GET", "/api/v1/tokens", nil)
+	request.Header.Set("Authorization", "Bearer "+writer.PlainTextToken)
+	response, _ = app.Test(request)
+	assert.Equal(t, 403, response.StatusCode)
+
+	// The id of the token to revoke is validated
+	response = postForm(t, "/api/v1/tokens/delete/abc", "", writer.PlainTextToken, nil)
+	assert.Equal(t, 400, response.StatusCode)
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"govel/app/connection"
+	"govel/app/http/middleware"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type queryRow struct {
+	ID   uint
+	Name string
+}
+
+func newQueryDatabase(t *testing.T, plugins ...gorm.Plugin) *gorm.DB {
+	database, err := gorm.Open(sqlite.Open(t.TempDir()+"/queries.db"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	assert.NoError(t, database.AutoMigrate(&queryRow{}))
+	assert.NoError(t, database.Create(&[]queryRow{{Name: "a"}, {Name: "b"}, {Name: "c"}}).Error)
+	for _, plugin := range plugins {
+		assert.NoError(t, database.Use(plugin))
+	}
+	return database
+}
+
+// Capture the records of the default logger as JSON lines until the test ends
+func captureLogs(t *testing.T) func() []map[string]interface{} {
+	previous := slog.Default()
+	output := &bytes.Buffer{}
+	slog.SetDefault(slog.New(slog.NewJSONHandler(output, nil)))
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+	})
+
+	return func() []map[string]interface{} {
+		records := []map[string]interface{}{}
+		for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+			record := map[string]interface{}{}
+			if json.Unmarshal([]byte(line), &record) == nil {
+				records = append(records, record)
+			}
+		}
+		return records
+	}
+}
+
+func TestQueryLog_SlowQuery(t *testing.T) {
+	logs := captureLogs(t)
+
+	// The queries faster than the threshold aren't logged
+	fast := newQueryDatabase(t, &connection.SlowQueryLogger{Connection: "sqlite", Threshold: time.Hour})
+	fast.Where("name = ?", "a").Find(&[]queryRow{})
+	assert.Empty(t, logs())
+
+	slow := newQueryDatabase(t, &connection.SlowQueryLogger{Connection: "sqlite", Threshold: 0})
+	slow.Where("name = ?", "a").Find(&[]queryRow{})
+	records := logs()
+	assert.Len(t, records, 1)
+	assert.Equal(t, "WARN", records[0]["level"])
+	assert.Equal(t, "slow query", records[0]["msg"])
+	assert.Equal(t, "sqlite", records[0]["connection"])
+	assert.Equal(t, "SELECT * FROM `query_rows` WHERE name = \"a\"", records[0]["sql"])
+	assert.Equal(t, float64(1), records[0]["rows"])
+	assert.Contains(t, records[0]["caller"], "query_log_test.go")
+}
+
+func TestQueryLog_Repeated(t *testing.T) {
+	database := newQueryDatabase(t, &connection.QueryLogger{})
+	ctx := connection.WithQueryLog(context.Background())
+
+	// The same statement with other values is repeated
+	for _, id := range []uint{1, 2, 3} {
+		database.WithContext(ctx).First(&queryRow{}, id)
+	}
+	database.WithContext(ctx).Where("name = ?", "a").Find(&[]queryRow{})
+	database.WithContext(ctx).Where("name = ?", "b").Find(&[]queryRow{})
+
+	queries := connection.QueryLogFrom(ctx)
+	assert.Len(t, queries.Queries(), 5)
+	assert.Equal(t, "SELECT * FROM `query_rows` WHERE `query_rows`.`id` = 2 ORDER BY `query_rows`.`id` LIMIT 1", queries.Queries()[1].SQL)
+
+	repeated := queries.Repeated(3)
+	assert.Len(t, repeated, 1)
+	assert.Equal(t, 3, repeated[0].Count)
+	assert.Equal(t, "SELECT * FROM `query_rows` WHERE `query_rows`.`id` = ? ORDER BY `query_rows`.`id` LIMIT 1", repeated[0].Statement)
+	assert.Contains(t, repeated[0].Caller, "query_log_test.go")
+
+	// In the order of the first run
+	repeated = queries.Repeated(2)
+	assert.Len(t, repeated, 2)
+	assert.Equal(t, 3, repeated[0].Count)
+	assert.Equal(t, 2, repeated[1].Count)
+	assert.Equal(t, "SELECT * FROM `query_rows` WHERE name = ?", repeated[1].Statement)
+}
+
+func TestQueryLog_InspectQueries(t *testing.T) {
+	database := newQueryDatabase(t, &connection.QueryLogger{})
+	inspectApp := func(repeatedThreshold int) *fiber.App {
+		inspectApp := fiber.New()
+		inspectApp.Use(middleware.InspectQueries(repeatedThreshold))
+		inspectApp.Get("/rows", func(c *fiber.Ctx) error {
+			for _, id := range []uint{1, 2, 3} {
+				database.WithContext(c.UserContext()).First(&queryRow{}, id)
+			}
+			return c.SendString("ok")
+		})
+		return inspectApp
+	}
+	logs := captureLogs(t)
+
+	response, _ := inspectApp(3).Test(httptest.NewRequest("GET", "/rows", nil))
+	assert.Equal(t, "3", response.Header.Get(middleware.DebugQueriesHeader))
+	records := logs()
+	assert.Len(t, records, 1)
+	assert.Equal(t, "WARN", records[0]["level"])
+	assert.Equal(t, "repeated query, likely N+1", records[0]["msg"])
+	assert.Equal(t, "/rows", records[0]["path"])
+	assert.Equal(t, float64(3), records[0]["count"])
+
+	// The zero threshold counts the queries without warning
+	response, _ = inspectApp(0).Test(httptest.NewRequest("GET", "/rows", nil))
+	assert.Equal(t, "3", response.Header.Get(middleware.DebugQueriesHeader))
+	assert.Len(t, logs(), 1)
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"context"
+	"govel/app/httpclient"
+	"govel/app/queue"
+	"govel/app/requestid"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestRequestID_Normalize(t *testing.T) {
+	assert.Equal(t, "abc-123", requestid.Normalize("abc-123"))
+	assert.Len(t, requestid.Normalize(""), 36)
+	assert.Len(t, requestid.Normalize("with space"), 36)
+	assert.Len(t, requestid.Normalize(strings.Repeat("a", 129)), 36)
+}
+
+func TestRequestID_HTTPClient(t *testing.T) {
+	received := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(requestid.Header)
+	}))
+	defer server.Close()
+
+	ctx := requestid.WithContext(context.Background(), "abc-123")
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	response, err := httpclient.New(time.Second, noop.NewTracerProvider()).Do(request)
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, "abc-123", received)
+	assert.Empty(t, request.Header.Get(requestid.Header))
+}
+
+func TestRequestID_Queue(t *testing.T) {
+	jobs := queue.NewSync()
+	handled := queue.Job{}
+	jobs.Handle("welcome", func(ctx context.Context, job queue.Job) error {
+		handled = job
+		assert.Equal(t, "abc-123", requestid.FromContext(ctx))
+		return nil
+	})
+
+	ctx := requestid.WithContext(context.Background(), "abc-123")
+	assert.NoError(t, jobs.Dispatch(ctx, "welcome", map[string]uint{"user_id": 1}))
+	assert.Equal(t, "abc-123", handled.RequestID)
+	payload := map[string]uint{}
+	assert.NoError(t, handled.Decode(&payload))
+	assert.Equal(t, uint(1), payload["user_id"])
+
+	assert.EqualError(t, jobs.Dispatch(ctx, "missing", nil), `job "missing" has no handler`)
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"context"
+	"govel/app/schedule"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule_Every(t *testing.T) {
+	tasks := schedule.New()
+	runs := int32(0)
+	tasks.Every(10*time.Millisecond, "count", func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	})
+	tasks.Every(10*time.Millisecond, "panic", func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	tasks.Start()
+	time.Sleep(55 * time.Millisecond)
+	assert.NoError(t, tasks.Stop(context.Background()))
+	stopped := atomic.LoadInt32(&runs)
+	assert.GreaterOrEqual(t, stopped, int32(3))
+
+	// No run after stopped
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, atomic.LoadInt32(&runs))
+}
+
+func TestSchedule_RunTask(t *testing.T) {
+	err := schedule.RunTask(context.Background(), schedule.Task{
+		Name: "panic",
+		Run: func(ctx context.Context) error {
+			panic("boom")
+		},
+	})
+	assert.EqualError(t, err, "panic: boom")
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"govel/app/helper"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Secret "12345678901234567890" of the RFC 6238 test vectors
+const totpSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTP_Code(t *testing.T) {
+	assert.Equal(t, "287082", helper.TOTPCode(totpSecret, time.Unix(59, 0)))
+	assert.Equal(t, "081804", helper.TOTPCode(totpSecret, time.Unix(1111111109, 0)))
+	assert.Equal(t, "005924", helper.TOTPCode(totpSecret, time.Unix(1234567890, 0)))
+	assert.Equal(t, "279037", helper.TOTPCode(totpSecret, time.Unix(2000000000, 0)))
+}
+
+func TestTOTP_Validate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	// Accept the current, previous and next period
+	assert.True(t, helper.TOTPValidate(totpSecret, "005924", now))
+	assert.True(t, helper.TOTPValidate(totpSecret, "005924", now.Add(30*time.Second)))
+	assert.True(t, helper.TOTPValidate(totpSecret, "005924", now.Add(-30*time.Second)))
+
+	// Reject the expired or wrong code
+	assert.False(t, helper.TOTPValidate(totpSecret, "005924", now.Add(90*time.Second)))
+	assert.False(t, helper.TOTPValidate(totpSecret, "123456", now))
+	assert.False(t, helper.TOTPValidate(totpSecret, "", now))
+}
+
+func TestTOTP_Verify(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	step, ok := helper.TOTPVerify(totpSecret, "005924", now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/helper.TOTPPeriod, step)
+
+	// Reject the code of the last used step and before
+	_, ok = helper.TOTPVerify(totpSecret, "005924", now.Add(30*time.Second), step)
+	assert.False(t, ok)
+	_, ok = helper.TOTPVerify(totpSecret, helper.TOTPCode(totpSecret, now.Add(-30*time.Second)), now, step)
+	assert.False(t, ok)
+
+	// Accept the code of the next step
+	next, ok := helper.TOTPVerify(totpSecret, helper.TOTPCode(totpSecret, now.Add(30*time.Second)), now, step)
+	assert.True(t, ok)
+	assert.Equal(t, step+1, next)
+}
+
+func TestTOTP_URI(t *testing.T) {
+	uri := helper.TOTPURI("Govel", "user@example.com", totpSecret)
+	assert.Contains(t, uri, "otpauth://totp/Govel:user@example.com?")
+	assert.Contains(t, uri, "secret="+totpSecret)
+	assert.Contains(t, uri, "issuer=Govel")
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"govel/app/http/middleware"
+	"govel/app/httpclient"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/opentelemetry/tracing"
+)
+
+func TestTrace_Request(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	database, err := gorm.Open(sqlite.Open(t.TempDir()+"/trace.db"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	assert.NoError(t, database.Use(tracing.NewPlugin(tracing.WithTracerProvider(provider), tracing.WithoutMetrics())))
+
+	// The called service gets the trace of the outgoing call
+	traceparent := ""
+	called := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer called.Close()
+
+	traceApp := fiber.New()
+	traceApp.Use(middleware.Trace(provider))
+	traceApp.Get("/users/:id", func(c *fiber.Ctx) error {
+		database.WithContext(c.UserContext()).Exec("SELECT 1")
+		request, _ := http.NewRequestWithContext(c.UserContext(), "GET", called.URL, nil)
+		response, err := httpclient.New(time.Second, provider).Do(request)
+		assert.NoError(t, err)
+		response.Body.Close()
+		return c.SendString("ok")
+	})
+
+	// The trace of the caller is continued
+	request := httptest.NewRequest("GET", "/users/1", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	traceApp.Test(request)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 3)
+	query, call, server := spans[0], spans[1], spans[2]
+	assert.Equal(t, "GET /users/:id", server.Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+
+	// The query and the call are the children of the request
+	assert.Equal(t, server.SpanContext.SpanID(), query.Parent.SpanID())
+	assert.Equal(t, server.SpanContext.SpanID(), call.Parent.SpanID())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+call.SpanContext.SpanID().String()+"-01", traceparent)
+}
@@ -0,0 +1,34 @@
+package test
+
+import (
+	"govel/app/helper"
+	"govel/app/model"
+	"testing"
+	"time"
+
+	"github.com/mintance/go-uniqid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoFactorController_DisableReplay(t *testing.T) {
+	email := uniqid.New(uniqid.Params{Prefix: "govel", MoreEntropy: true}) + "@gmail.com"
+	postForm(t, "/api/v1/users/register", "email="+email+"&name=Two Factor&password=rahasia&repassword=rahasia", "", nil)
+	login := model.TokenResponse{}
+	postForm(t, "/api/v1/users/login", "email="+email+"&password=rahasia", "", &login)
+
+	enabled := model.EnableTwoFactorResponse{}
+	response := postForm(t, "/api/v1/two-factor/enable", "token="+login.Token, "", &enabled)
+	assert.Equal(t, 200, response.StatusCode)
+	code := helper.TOTPCode(enabled.Secret, time.Now())
+	response = postForm(t, "/api/v1/two-factor/confirm", "token="+login.Token+"&code="+code, "", nil)
+	assert.Equal(t, 200, response.StatusCode)
+
+	// The code used to confirm can't disable it
+	response = postForm(t, "/api/v1/two-factor/disable", "token="+login.Token+"&code="+code, "", nil)
+	assert.Equal(t, 400, response.StatusCode)
+
+	// The code of the next time step can
+	next := helper.TOTPCode(enabled.Secret, time.Now().Add(helper.TOTPPeriod*time.Second))
+	response = postForm(t, "/api/v1/two-factor/disable", "token="+login.Token+"&code="+next, "", nil)
+	assert.Equal(t, 200, response.StatusCode)
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"govel/app/entity"
+	"govel/app/hashing"
+	"govel/app/model"
+	"govel/app/repository"
+	"govel/app/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Hasher counting the hashes made and checked
+type countingHasher struct {
+	makes  int
+	checks int
+}
+
+func (hasher *countingHasher) Make(value string) string {
+	hasher.makes++
+	return "$2y$" + value
+}
+
+func (hasher *countingHasher) Check(value string, hashed string) bool {
+	hasher.checks++
+	return hashed == "$2y$"+value
+}
+
+func (hasher *countingHasher) NeedsRehash(hashed string) bool {
+	return false
+}
+
+func TestUserService_LoginUnknownEmail(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(t.TempDir()+"/login.db"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	assert.NoError(t, database.AutoMigrate(&entity.LoginAttempt{}))
+	// The fulltext index of MySQL fails on sqlite, the table is created first
+	database.AutoMigrate(&entity.User{})
+	assert.True(t, database.Migrator().HasTable(&entity.User{}))
+	userRepository := repository.NewUserRepository(database)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(database)
+	throttle := service.LoginThrottle{MaxAttempts: 100, MaxIpAttempts: 100, Decay: time.Minute, Lockout: time.Minute, MaxLockout: time.Minute}
+
+	hasher := &countingHasher{}
+	dummyHash := hashing.NewDummyHash(hasher)
+	assert.Equal(t, 1, hasher.makes)
+
+	// Every request has its own service, the dummy hash is only checked
+	for i := 0; i < 3; i++ {
+		userService := service.NewUserService(&userRepository, &loginAttemptRepository, throttle, hasher, dummyHash)
+		assert.Panics(t, func() {
+			userService.Login(model.LoginUserRequest{Email: "missing@gmail.com", Password: "rahasia", Ip: "10.0.0.1"})
+		})
+	}
+	assert.Equal(t, 1, hasher.makes)
+	assert.Equal(t, 3, hasher.checks)
+}
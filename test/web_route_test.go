@@ -0,0 +1,22 @@
+package test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebRoute_Session(t *testing.T) {
+	response, _ := app.Test(httptest.NewRequest("GET", "/csrf-token", nil))
+	assert.Equal(t, 200, response.StatusCode)
+	assert.NotEmpty(t, response.Header.Get("Set-Cookie"))
+
+	// The session is not started without a route
+	response, _ = app.Test(httptest.NewRequest("GET", "/not-found", nil))
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Empty(t, response.Header.Get("Set-Cookie"))
+
+	response, _ = app.Test(httptest.NewRequest("POST", "/not-found", nil))
+	assert.Equal(t, 404, response.StatusCode)
+}